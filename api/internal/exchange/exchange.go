@@ -0,0 +1,247 @@
+// Пакет exchange переводит тесты в форматы Moodle XML и GIFT и обратно.
+// Экспорт работает с ключом ответов преподавателя, импорт собирает запросы
+// создания вопросов и перечисляет конструкции, которые не удалось перенести.
+package exchange
+
+import (
+	"api/internal/models"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые форматы
+const (
+	FormatMoodleXML = "moodle"
+	FormatGIFT      = "gift"
+)
+
+// Серьезность замечания к импортируемому вопросу
+const (
+	SeverityWarning = "warning" // вопрос импортирован, но часть конструкций потеряна
+	SeveritySkipped = "skipped" // вопрос не импортирован
+)
+
+// Типы вопросов и правил, как они хранятся в БД
+const (
+	typeSingleChoice   = "single_choice"
+	typeMultipleChoice = "multiple_choice"
+	typeTextAnswer     = "text_answer"
+	typeMatching       = "matching"
+	typeRelation       = "relation"
+
+	ruleExact      = "exact"
+	ruleNormalized = "normalized"
+	ruleRegex      = "regex"
+	ruleNumeric    = "numeric"
+
+	scoringPartial = "partial"
+)
+
+// Подписи вариантов для вопросов «верно/неверно»
+const (
+	optionTrue  = "Верно"
+	optionFalse = "Неверно"
+)
+
+var ErrUnknownFormat = errors.New("unknown exchange format")
+
+// ImportedQuestion — вопрос файла, который удалось перевести в запрос создания
+type ImportedQuestion struct {
+	Index    int // номер вопроса в файле с 1
+	Title    string
+	Question models.CreateQuestionRequest
+}
+
+// ImportResult — результат разбора файла
+type ImportResult struct {
+	Title     string // название из категории или комментария, если есть
+	Questions []ImportedQuestion
+	Issues    []models.ImportIssue
+	Skipped   int
+}
+
+func (r *ImportResult) warn(index int, title, format string, args ...any) {
+	r.Issues = append(r.Issues, models.ImportIssue{
+		Question: index,
+		Title:    title,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *ImportResult) skip(index int, title, format string, args ...any) {
+	r.Skipped++
+	r.Issues = append(r.Issues, models.ImportIssue{
+		Question: index,
+		Title:    title,
+		Severity: SeveritySkipped,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Export сериализует тест с ключом ответов в указанный формат
+func Export(format string, test *models.Test, questions []models.TeacherQuestion) ([]byte, error) {
+	switch format {
+	case FormatMoodleXML:
+		return exportMoodle(test, questions)
+	case FormatGIFT:
+		return exportGIFT(test, questions), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// Import разбирает файл в указанном формате
+func Import(format string, data []byte) (*ImportResult, error) {
+	switch format {
+	case FormatMoodleXML:
+		return importMoodle(data)
+	case FormatGIFT:
+		return importGIFT(data), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// FileExtension возвращает расширение файла для формата
+func FileExtension(format string) string {
+	if format == FormatMoodleXML {
+		return ".xml"
+	}
+	return ".gift.txt"
+}
+
+// ContentType возвращает MIME-тип файла для формата
+func ContentType(format string) string {
+	if format == FormatMoodleXML {
+		return "application/xml; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// questionName — короткое имя вопроса для форматов, где оно обязательно
+func questionName(q models.TeacherQuestion, n int) string {
+	text := []rune(strings.Join(strings.Fields(q.QuestionText), " "))
+	if len(text) > 40 {
+		return string(text[:40]) + "…"
+	}
+	if len(text) == 0 {
+		return fmt.Sprintf("Вопрос %d", n)
+	}
+	return string(text)
+}
+
+// percent форматирует долю оценки варианта в процентах без лишних нулей
+func percent(value float64) string {
+	s := strconv.FormatFloat(value, 'f', 5, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// correctShare — доля оценки каждого правильного варианта множественного выбора
+func correctShare(options []models.AnswerOption) float64 {
+	correct := 0
+	for _, o := range options {
+		if o.IsCorrect {
+			correct++
+		}
+	}
+	if correct == 0 {
+		return 0
+	}
+	return 100 / float64(correct)
+}
+
+// textRulesKind определяет, как правила текстового ответа переносятся в сторонний формат:
+// "short" — только exact/normalized, "numeric" — только числа, "" — без правил
+// (ручная проверка), "other" — есть правила, которые форматы выразить не могут
+func textRulesKind(rules []models.TextAnswerRule) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	short, numeric := 0, 0
+	for _, r := range rules {
+		switch r.RuleType {
+		case ruleExact, ruleNormalized:
+			short++
+		case ruleNumeric:
+			numeric++
+		}
+	}
+	switch {
+	case short == len(rules):
+		return "short"
+	case numeric == len(rules):
+		return "numeric"
+	default:
+		return "other"
+	}
+}
+
+// wildcardRule переводит образец короткого ответа Moodle в правило проверки:
+// звездочка означает любую подстроку, поэтому такие образцы становятся regex
+func wildcardRule(pattern string, caseSensitive bool) models.CreateTextAnswerRuleRequest {
+	if !strings.Contains(pattern, "*") {
+		ruleType := ruleNormalized
+		if caseSensitive {
+			ruleType = ruleExact
+		}
+		return models.CreateTextAnswerRuleRequest{RuleType: ruleType, Pattern: pattern}
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := strings.Join(parts, ".*")
+	if !caseSensitive {
+		expr = "(?i)" + expr
+	}
+	return models.CreateTextAnswerRuleRequest{RuleType: ruleRegex, Pattern: expr}
+}
+
+// parsePoints переводит оценку вопроса в целые баллы
+func parsePoints(s string) (int, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value < 0 {
+		return 1, false
+	}
+	points := int(math.Round(value))
+	return points, float64(points) == value
+}
+
+var (
+	htmlTagRe   = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlBreakRe = regexp.MustCompile(`(?i)<br\s*/?>|</p>`)
+)
+
+// stripHTML оставляет от HTML-разметки Moodle только текст
+func stripHTML(s string) string {
+	s = htmlBreakRe.ReplaceAllString(s, "\n")
+	s = htmlTagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// numberPositions выставляет позиции вариантов, пар и правил по порядку в файле
+func numberPositions(q *models.CreateQuestionRequest) {
+	for i := range q.Options {
+		q.Options[i].Position = i + 1
+	}
+	for i := range q.Pairs {
+		q.Pairs[i].Position = i + 1
+	}
+	for i := range q.AnswerRules {
+		q.AnswerRules[i].Position = i + 1
+	}
+}
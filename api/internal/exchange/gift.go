@@ -0,0 +1,510 @@
+package exchange
+
+import (
+	"api/internal/models"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Формат GIFT (https://docs.moodle.org/en/GIFT_format). Баллы вопроса в GIFT
+// не задаются, поэтому при экспорте они пишутся комментарием "// points: N"
+// перед вопросом и читаются из него при импорте.
+
+var giftPointsRe = regexp.MustCompile(`(?i)^//\s*points\s*:\s*(\S+)`)
+
+// Символы, которые в тексте GIFT экранируются обратной косой чертой
+const giftSpecial = "~=#{}:\\"
+
+type giftItem struct {
+	mark     byte // '=' или '~'
+	weight   float64
+	weighted bool
+	text     string // без веса и отзыва, еще экранированный
+	feedback bool
+}
+
+func exportGIFT(test *models.Test, questions []models.TeacherQuestion) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "$CATEGORY: $course$/%s\n\n", test.Title)
+
+	for i, q := range questions {
+		fmt.Fprintf(&buf, "// points: %d\n", q.Points)
+		var body string
+		switch q.QuestionType {
+		case typeSingleChoice:
+			var items []string
+			for _, o := range q.Options {
+				mark := "~"
+				if o.IsCorrect {
+					mark = "="
+				}
+				items = append(items, mark+giftEscape(o.OptionText))
+			}
+			body = giftBlock(items)
+		case typeMultipleChoice:
+			share := correctShare(q.Options)
+			var items []string
+			for _, o := range q.Options {
+				weight := "0"
+				if o.IsCorrect {
+					weight = percent(share)
+				}
+				items = append(items, "~%"+weight+"%"+giftEscape(o.OptionText))
+			}
+			body = giftBlock(items)
+		case typeMatching:
+			if q.ScoringMode != scoringPartial {
+				buf.WriteString("// all-or-nothing scoring is not supported by GIFT; pairs are graded separately\n")
+			}
+			var items []string
+			for _, p := range q.Pairs {
+				items = append(items, "="+giftEscape(p.LeftText)+" -> "+giftEscape(p.RightText))
+			}
+			body = giftBlock(items)
+		case typeTextAnswer:
+			body = giftTextBody(&buf, q.AnswerRules)
+		case typeRelation:
+			buf.WriteString("// relation questions are not supported by GIFT; exported as essay for manual grading\n")
+			body = "{}"
+		}
+
+		fmt.Fprintf(&buf, "::%s::%s%s\n\n", giftEscape(questionName(q, i+1)), giftEscape(q.QuestionText), body)
+	}
+
+	return buf.Bytes()
+}
+
+// giftTextBody формирует блок ответа открытого вопроса; пояснения о потерях пишутся в buf
+func giftTextBody(buf *bytes.Buffer, rules []models.TextAnswerRule) string {
+	switch textRulesKind(rules) {
+	case "short":
+		var items []string
+		exact := false
+		for _, r := range rules {
+			exact = exact || r.RuleType == ruleExact
+			items = append(items, "="+giftEscape(r.Pattern))
+		}
+		if exact {
+			buf.WriteString("// exact rules are exported as case-insensitive answers\n")
+		}
+		return giftBlock(items)
+	case "numeric":
+		if len(rules) == 1 {
+			return "{#" + giftNumber(rules[0]) + "}"
+		}
+		items := make([]string, 0, len(rules))
+		for _, r := range rules {
+			items = append(items, "="+giftNumber(r))
+		}
+		return "{#\n\t" + strings.Join(items, "\n\t") + "\n}"
+	case "other":
+		buf.WriteString("// answer rules are not representable in GIFT; exported as essay for manual grading\n")
+	}
+	return "{}"
+}
+
+func giftBlock(items []string) string {
+	return " {\n\t" + strings.Join(items, "\n\t") + "\n}"
+}
+
+func giftNumber(r models.TextAnswerRule) string {
+	pattern := strings.ReplaceAll(strings.TrimSpace(r.Pattern), ",", ".")
+	return pattern + ":" + strconv.FormatFloat(r.Tolerance, 'f', -1, 64)
+}
+
+func giftEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+		case strings.ContainsRune(giftSpecial, r):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func giftUnescape(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped && r == 'n':
+			b.WriteByte('\n')
+		case escaped && strings.ContainsRune(giftSpecial, r):
+			b.WriteRune(r)
+		case escaped:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\\':
+			escaped = true
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		escaped = false
+	}
+	if escaped {
+		b.WriteByte('\\')
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// giftIndex возвращает позицию первого неэкранированного символа из chars, начиная с from
+func giftIndex(s string, chars string, from int) int {
+	escaped := false
+	for i := from; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case strings.IndexByte(chars, s[i]) >= 0:
+			return i
+		}
+	}
+	return -1
+}
+
+func importGIFT(data []byte) *ImportResult {
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	result := &ImportResult{}
+	index := 0
+	points := ""
+	var block []string
+
+	flush := func() {
+		if len(block) > 0 {
+			index++
+			giftImportQuestion(result, index, strings.Join(block, "\n"), points)
+		}
+		block, points = nil, ""
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+			if m := giftPointsRe.FindStringSubmatch(trimmed); m != nil {
+				points = m[1]
+			}
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			if result.Title == "" {
+				path := strings.Split(strings.TrimSpace(strings.TrimPrefix(trimmed, "$CATEGORY:")), "/")
+				if title := strings.TrimSpace(path[len(path)-1]); !strings.HasPrefix(title, "$") {
+					result.Title = title
+				}
+			}
+		default:
+			block = append(block, line)
+		}
+	}
+	flush()
+
+	return result
+}
+
+func giftImportQuestion(result *ImportResult, index int, raw, points string) {
+	raw = strings.TrimSpace(raw)
+
+	title := ""
+	if strings.HasPrefix(raw, "::") {
+		for end := giftIndex(raw, ":", 2); end >= 0; end = giftIndex(raw, ":", end+1) {
+			if strings.HasPrefix(raw[end:], "::") {
+				title = giftUnescape(raw[2:end])
+				raw = strings.TrimSpace(raw[end+2:])
+				break
+			}
+		}
+	}
+
+	textFormat := ""
+	if strings.HasPrefix(raw, "[") {
+		if end := strings.IndexByte(raw, ']'); end > 0 {
+			textFormat = raw[1:end]
+			raw = raw[end+1:]
+		}
+	}
+	plain := func(s string) string {
+		s = giftUnescape(s)
+		if textFormat == "html" {
+			return stripHTML(s)
+		}
+		return s
+	}
+
+	open := giftIndex(raw, "{", 0)
+	if open < 0 {
+		result.skip(index, title, "description items have no answer")
+		return
+	}
+	closing := giftIndex(raw, "}", open+1)
+	if closing < 0 {
+		result.skip(index, title, "answer block is not closed")
+		return
+	}
+
+	q := models.CreateQuestionRequest{QuestionText: plain(raw[:open]), Points: 1}
+	if after := strings.TrimSpace(raw[closing+1:]); after != "" {
+		q.QuestionText = strings.TrimSpace(q.QuestionText + " ___ " + plain(after))
+		result.warn(index, title, "missing word format is not supported; the gap is shown as ___")
+	}
+	if points != "" {
+		value, exact := parsePoints(points)
+		if !exact {
+			result.warn(index, title, "points %s rounded to %d", points, value)
+		}
+		q.Points = value
+	}
+
+	body := strings.TrimSpace(raw[open+1 : closing])
+	upper := strings.ToUpper(strings.TrimSpace(giftUnescape(cutFeedback(body))))
+
+	switch {
+	case body == "":
+		q.QuestionType = typeTextAnswer
+	case strings.HasPrefix(body, "#"):
+		if !giftNumeric(result, index, title, body[1:], &q) {
+			return
+		}
+	case upper == "T" || upper == "TRUE" || upper == "F" || upper == "FALSE":
+		if cutFeedback(body) != body {
+			result.warn(index, title, "answer feedback is not supported and was dropped")
+		}
+		correct := upper == "T" || upper == "TRUE"
+		q.QuestionType = typeSingleChoice
+		q.Options = []models.CreateAnswerOptionRequest{
+			{OptionText: optionTrue, IsCorrect: correct},
+			{OptionText: optionFalse, IsCorrect: !correct},
+		}
+	default:
+		items, ok := giftItems(body)
+		if !ok {
+			result.skip(index, title, "answer block must start with = or ~")
+			return
+		}
+		giftChoice(result, index, title, items, plain, &q)
+	}
+
+	numberPositions(&q)
+	result.Questions = append(result.Questions, ImportedQuestion{Index: index, Title: title, Question: q})
+}
+
+// giftChoice разбирает варианты, короткие ответы или пары сопоставления
+func giftChoice(result *ImportResult, index int, title string, items []giftItem, plain func(string) string, q *models.CreateQuestionRequest) {
+	equals, tildes, positiveTildes, matching := 0, 0, 0, 0
+	for _, item := range items {
+		if item.feedback {
+			result.warn(index, title, "answer feedback is not supported and was dropped")
+			break
+		}
+	}
+	for _, item := range items {
+		if item.mark == '=' {
+			equals++
+			if strings.Contains(item.text, "->") {
+				matching++
+			}
+		} else {
+			tildes++
+			if item.weight > 0 {
+				positiveTildes++
+			}
+		}
+	}
+
+	switch {
+	case matching > 0 && tildes == 0:
+		q.QuestionType = typeMatching
+		q.ScoringMode = scoringPartial
+		for _, item := range items {
+			left, right, ok := strings.Cut(item.text, "->")
+			if !ok || strings.TrimSpace(left) == "" {
+				result.warn(index, title, "extra distractor %q is not supported and was dropped", plain(right))
+				continue
+			}
+			q.Pairs = append(q.Pairs, models.CreateMatchingPairRequest{LeftText: plain(left), RightText: plain(right)})
+		}
+
+	case tildes == 0:
+		q.QuestionType = typeTextAnswer
+		for _, item := range items {
+			if item.weighted && item.weight < 100 {
+				result.warn(index, title, "partial credit for answer %q is not supported; answer dropped", plain(item.text))
+				continue
+			}
+			q.AnswerRules = append(q.AnswerRules, wildcardRule(plain(item.text), false))
+		}
+
+	case equals == 0 && positiveTildes > 0:
+		q.QuestionType = typeMultipleChoice
+		weights := map[string]bool{}
+		penalties := false
+		for _, item := range items {
+			q.Options = append(q.Options, models.CreateAnswerOptionRequest{OptionText: plain(item.text), IsCorrect: item.weight > 0})
+			if item.weight > 0 {
+				weights[percent(item.weight)] = true
+			}
+			penalties = penalties || item.weight < 0
+		}
+		if penalties {
+			result.warn(index, title, "negative grades for wrong options are not supported; wrong options give 0")
+		}
+		if len(weights) > 1 {
+			result.warn(index, title, "correct options have different weights; they are weighted equally")
+		}
+
+	default:
+		q.QuestionType = typeSingleChoice
+		if equals > 1 {
+			q.QuestionType = typeMultipleChoice
+			result.warn(index, title, "several answers are marked correct; imported as multiple choice")
+		}
+		if positiveTildes > 0 {
+			result.warn(index, title, "partially correct options are not supported; they are treated as incorrect")
+		}
+		for _, item := range items {
+			q.Options = append(q.Options, models.CreateAnswerOptionRequest{OptionText: plain(item.text), IsCorrect: item.mark == '='})
+		}
+	}
+}
+
+// giftNumeric разбирает числовой ответ: {#3.14:0.01}, {#1..5} или {#=1:0 =2:0};
+// возвращает false, если вопрос пропущен
+func giftNumeric(result *ImportResult, index int, title, body string, q *models.CreateQuestionRequest) bool {
+	q.QuestionType = typeTextAnswer
+	body = strings.TrimSpace(body)
+
+	specs := []giftItem{{mark: '=', weight: 100, text: body}}
+	if strings.HasPrefix(body, "=") || strings.HasPrefix(body, "~") {
+		var ok bool
+		if specs, ok = giftItems(body); !ok {
+			result.skip(index, title, "malformed numeric answer")
+			return false
+		}
+	} else if cut := cutFeedback(body); cut != body {
+		specs[0].text, specs[0].feedback = cut, true
+	}
+
+	for _, spec := range specs {
+		if spec.feedback {
+			result.warn(index, title, "answer feedback is not supported and was dropped")
+			break
+		}
+	}
+
+	for _, spec := range specs {
+		if spec.mark == '~' || (spec.weighted && spec.weight <= 0) {
+			continue
+		}
+		if spec.weighted && spec.weight < 100 {
+			result.warn(index, title, "partial credit for answer %s is not supported; answer dropped", spec.text)
+			continue
+		}
+		value, tolerance, err := giftNumberSpec(spec.text)
+		if err != nil {
+			result.skip(index, title, "%v", err)
+			return false
+		}
+		q.AnswerRules = append(q.AnswerRules, models.CreateTextAnswerRuleRequest{
+			RuleType:  ruleNumeric,
+			Pattern:   strconv.FormatFloat(value, 'f', -1, 64),
+			Tolerance: tolerance,
+		})
+	}
+
+	if len(q.AnswerRules) == 0 {
+		result.warn(index, title, "no fully correct answers; all answers will be graded manually")
+	}
+	return true
+}
+
+// giftNumberSpec разбирает "число:погрешность" или диапазон "мин..макс"
+func giftNumberSpec(spec string) (float64, float64, error) {
+	spec = strings.TrimSpace(spec)
+	if low, high, ok := strings.Cut(spec, ".."); ok {
+		a, errA := strconv.ParseFloat(strings.TrimSpace(low), 64)
+		b, errB := strconv.ParseFloat(strings.TrimSpace(high), 64)
+		if errA != nil || errB != nil || b < a {
+			return 0, 0, fmt.Errorf("invalid numeric range %q", spec)
+		}
+		return (a + b) / 2, (b - a) / 2, nil
+	}
+
+	number, tol, _ := strings.Cut(spec, ":")
+	value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid number %q", spec)
+	}
+	tolerance := 0.0
+	if strings.TrimSpace(tol) != "" {
+		tolerance, err = strconv.ParseFloat(strings.TrimSpace(tol), 64)
+		if err != nil || tolerance < 0 {
+			return 0, 0, fmt.Errorf("invalid tolerance %q", spec)
+		}
+	}
+	return value, tolerance, nil
+}
+
+// giftItems делит блок ответа на варианты по неэкранированным = и ~
+func giftItems(body string) ([]giftItem, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" || (body[0] != '=' && body[0] != '~') {
+		return nil, false
+	}
+
+	var items []giftItem
+	for start := 0; start < len(body); {
+		next := giftIndex(body, "=~", start+1)
+		end := next
+		if next < 0 {
+			end = len(body)
+		}
+
+		item := giftItem{mark: body[start]}
+		text := strings.TrimSpace(body[start+1 : end])
+		if strings.HasPrefix(text, "%") {
+			if end := strings.IndexByte(text[1:], '%'); end >= 0 {
+				if w, err := strconv.ParseFloat(text[1:1+end], 64); err == nil {
+					item.weight, item.weighted = w, true
+					text = strings.TrimSpace(text[2+end:])
+				}
+			}
+		}
+		if !item.weighted && item.mark == '=' {
+			item.weight = 100
+		}
+		if cut := cutFeedback(text); cut != text {
+			text, item.feedback = cut, true
+		}
+		item.text = text
+		items = append(items, item)
+
+		if next < 0 {
+			break
+		}
+		start = next
+	}
+
+	return items, true
+}
+
+// cutFeedback отбрасывает отзыв после неэкранированного #
+func cutFeedback(s string) string {
+	if i := giftIndex(s, "#", 0); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
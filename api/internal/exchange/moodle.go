@@ -0,0 +1,366 @@
+package exchange
+
+import (
+	"api/internal/models"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Структура файла Moodle XML (https://docs.moodle.org/en/Moodle_XML_format)
+// в объеме, который нужен для поддерживаемых типов вопросов
+
+type moodleQuiz struct {
+	XMLName   xml.Name         `xml:"quiz"`
+	Questions []moodleQuestion `xml:"question"`
+}
+
+type moodleText struct {
+	Format string `xml:"format,attr,omitempty"`
+	Text   string `xml:"text"`
+}
+
+type moodleQuestion struct {
+	Type            string              `xml:"type,attr"`
+	Comment         string              `xml:",comment"`
+	Category        *moodleText         `xml:"category,omitempty"`
+	Name            *moodleText         `xml:"name,omitempty"`
+	QuestionText    *moodleText         `xml:"questiontext,omitempty"`
+	GeneralFeedback *moodleText         `xml:"generalfeedback,omitempty"`
+	DefaultGrade    string              `xml:"defaultgrade,omitempty"`
+	Single          string              `xml:"single,omitempty"`
+	ShuffleAnswers  string              `xml:"shuffleanswers,omitempty"`
+	UseCase         string              `xml:"usecase,omitempty"`
+	Answers         []moodleAnswer      `xml:"answer"`
+	Subquestions    []moodleSubquestion `xml:"subquestion"`
+	Units           *moodleUnits        `xml:"units,omitempty"`
+}
+
+type moodleUnits struct {
+	Units []struct {
+		Name string `xml:"unit_name"`
+	} `xml:"unit"`
+}
+
+type moodleAnswer struct {
+	Fraction  string      `xml:"fraction,attr"`
+	Format    string      `xml:"format,attr,omitempty"`
+	Text      string      `xml:"text"`
+	Tolerance string      `xml:"tolerance,omitempty"`
+	Feedback  *moodleText `xml:"feedback,omitempty"`
+}
+
+type moodleSubquestion struct {
+	Format string     `xml:"format,attr,omitempty"`
+	Text   string     `xml:"text"`
+	Answer moodleText `xml:"answer"`
+}
+
+func exportMoodle(test *models.Test, questions []models.TeacherQuestion) ([]byte, error) {
+	quiz := moodleQuiz{}
+	quiz.Questions = append(quiz.Questions, moodleQuestion{
+		Type:     "category",
+		Category: &moodleText{Text: "$course$/" + test.Title},
+	})
+
+	for i, q := range questions {
+		quiz.Questions = append(quiz.Questions, moodleExportQuestion(q, i+1))
+	}
+
+	out, err := xml.MarshalIndent(quiz, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode moodle xml: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.Write(out)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func moodleExportQuestion(q models.TeacherQuestion, n int) moodleQuestion {
+	mq := moodleQuestion{
+		Name:         &moodleText{Text: questionName(q, n)},
+		QuestionText: &moodleText{Format: "plain_text", Text: q.QuestionText},
+		DefaultGrade: strconv.Itoa(q.Points),
+	}
+
+	switch q.QuestionType {
+	case typeSingleChoice, typeMultipleChoice:
+		mq.Type = "multichoice"
+		mq.Single = "true"
+		mq.ShuffleAnswers = "0"
+		share := 100.0
+		if q.QuestionType == typeMultipleChoice {
+			mq.Single = "false"
+			share = correctShare(q.Options)
+		}
+		for _, o := range q.Options {
+			fraction := "0"
+			if o.IsCorrect {
+				fraction = percent(share)
+			}
+			mq.Answers = append(mq.Answers, moodleAnswer{Fraction: fraction, Format: "plain_text", Text: o.OptionText})
+		}
+
+	case typeMatching:
+		mq.Type = "matching"
+		mq.ShuffleAnswers = "1"
+		if q.ScoringMode != scoringPartial {
+			mq.Comment = " all-or-nothing scoring is not supported by Moodle; pairs are graded separately "
+		}
+		for _, p := range q.Pairs {
+			mq.Subquestions = append(mq.Subquestions, moodleSubquestion{
+				Format: "plain_text",
+				Text:   p.LeftText,
+				Answer: moodleText{Text: p.RightText},
+			})
+		}
+
+	case typeTextAnswer:
+		switch textRulesKind(q.AnswerRules) {
+		case "short":
+			// Moodle задает чувствительность к регистру для всего вопроса
+			mq.Type = "shortanswer"
+			mq.UseCase = "0"
+			normalized := false
+			for _, r := range q.AnswerRules {
+				if r.RuleType == ruleExact {
+					mq.UseCase = "1"
+				} else {
+					normalized = true
+				}
+				mq.Answers = append(mq.Answers, moodleAnswer{Fraction: "100", Format: "plain_text", Text: r.Pattern})
+			}
+			if mq.UseCase == "1" && normalized {
+				mq.Comment = " normalized rules are exported as case-sensitive answers "
+			}
+		case "numeric":
+			mq.Type = "numerical"
+			for _, r := range q.AnswerRules {
+				mq.Answers = append(mq.Answers, moodleAnswer{
+					Fraction:  "100",
+					Text:      r.Pattern,
+					Tolerance: strconv.FormatFloat(r.Tolerance, 'f', -1, 64),
+				})
+			}
+		case "other":
+			mq.Type = "essay"
+			mq.Comment = " answer rules are not representable in Moodle XML; exported as essay for manual grading "
+		default:
+			mq.Type = "essay"
+		}
+
+	case typeRelation:
+		mq.Type = "essay"
+		mq.Comment = " relation questions are not supported by Moodle; exported as essay for manual grading "
+	}
+
+	return mq
+}
+
+func importMoodle(data []byte) (*ImportResult, error) {
+	var quiz moodleQuiz
+	if err := xml.Unmarshal(data, &quiz); err != nil {
+		return nil, fmt.Errorf("invalid moodle xml: %w", err)
+	}
+
+	result := &ImportResult{}
+	index := 0
+	for _, mq := range quiz.Questions {
+		if mq.Type == "category" {
+			// Название теста берется из последнего сегмента пути первой категории
+			if result.Title == "" && mq.Category != nil {
+				path := strings.Split(strings.TrimSpace(mq.Category.Text), "/")
+				if title := strings.TrimSpace(path[len(path)-1]); !strings.HasPrefix(title, "$") {
+					result.Title = title
+				}
+			}
+			continue
+		}
+
+		index++
+		moodleImportQuestion(result, index, mq)
+	}
+
+	return result, nil
+}
+
+func moodleImportQuestion(result *ImportResult, index int, mq moodleQuestion) {
+	title := ""
+	if mq.Name != nil {
+		title = strings.TrimSpace(mq.Name.Text)
+	}
+
+	text := ""
+	if mq.QuestionText != nil {
+		text = moodleTextValue(mq.QuestionText.Format, mq.QuestionText.Text)
+	}
+
+	q := models.CreateQuestionRequest{QuestionText: text, Points: 1}
+	if mq.DefaultGrade != "" {
+		points, exact := parsePoints(mq.DefaultGrade)
+		if !exact {
+			result.warn(index, title, "grade %s rounded to %d points", mq.DefaultGrade, points)
+		}
+		q.Points = points
+	}
+
+	if mq.GeneralFeedback != nil && strings.TrimSpace(mq.GeneralFeedback.Text) != "" {
+		result.warn(index, title, "general feedback is not supported and was dropped")
+	}
+	for _, a := range mq.Answers {
+		if a.Feedback != nil && strings.TrimSpace(a.Feedback.Text) != "" {
+			result.warn(index, title, "answer feedback is not supported and was dropped")
+			break
+		}
+	}
+
+	switch mq.Type {
+	case "multichoice":
+		if !moodleChoice(result, index, title, mq, &q) {
+			return
+		}
+	case "truefalse":
+		q.QuestionType = typeSingleChoice
+		correct := ""
+		for _, a := range mq.Answers {
+			if fraction(a.Fraction) >= 100 {
+				correct = strings.ToLower(strings.TrimSpace(a.Text))
+			}
+		}
+		q.Options = []models.CreateAnswerOptionRequest{
+			{OptionText: optionTrue, IsCorrect: correct == "true"},
+			{OptionText: optionFalse, IsCorrect: correct == "false"},
+		}
+	case "shortanswer":
+		q.QuestionType = typeTextAnswer
+		caseSensitive := strings.TrimSpace(mq.UseCase) == "1"
+		for _, a := range mq.Answers {
+			f := fraction(a.Fraction)
+			pattern := moodleTextValue(a.Format, a.Text)
+			switch {
+			case f >= 100 && pattern != "":
+				q.AnswerRules = append(q.AnswerRules, wildcardRule(pattern, caseSensitive))
+			case f > 0:
+				result.warn(index, title, "partial credit for answer %q is not supported; answer dropped", pattern)
+			}
+		}
+		if len(q.AnswerRules) == 0 {
+			result.warn(index, title, "no fully correct answers; all answers will be graded manually")
+		}
+	case "numerical":
+		q.QuestionType = typeTextAnswer
+		if mq.Units != nil && len(mq.Units.Units) > 0 {
+			result.warn(index, title, "units are not supported; only the number is checked")
+		}
+		for _, a := range mq.Answers {
+			f := fraction(a.Fraction)
+			pattern := strings.TrimSpace(a.Text)
+			switch {
+			case pattern == "*":
+				continue
+			case f >= 100:
+				tolerance, _ := strconv.ParseFloat(strings.TrimSpace(a.Tolerance), 64)
+				q.AnswerRules = append(q.AnswerRules, models.CreateTextAnswerRuleRequest{
+					RuleType:  ruleNumeric,
+					Pattern:   pattern,
+					Tolerance: tolerance,
+				})
+			case f > 0:
+				result.warn(index, title, "partial credit for answer %s is not supported; answer dropped", pattern)
+			}
+		}
+		if len(q.AnswerRules) == 0 {
+			result.warn(index, title, "no fully correct answers; all answers will be graded manually")
+		}
+	case "matching":
+		q.QuestionType = typeMatching
+		q.ScoringMode = scoringPartial
+		for _, sq := range mq.Subquestions {
+			left := moodleTextValue(sq.Format, sq.Text)
+			if left == "" {
+				result.warn(index, title, "extra distractor %q is not supported and was dropped", sq.Answer.Text)
+				continue
+			}
+			q.Pairs = append(q.Pairs, models.CreateMatchingPairRequest{
+				LeftText:  left,
+				RightText: strings.TrimSpace(sq.Answer.Text),
+			})
+		}
+	case "essay":
+		q.QuestionType = typeTextAnswer
+	case "description":
+		result.skip(index, title, "description items have no answer")
+		return
+	default:
+		result.skip(index, title, "question type %s is not supported", mq.Type)
+		return
+	}
+
+	numberPositions(&q)
+	result.Questions = append(result.Questions, ImportedQuestion{Index: index, Title: title, Question: q})
+}
+
+// moodleChoice переводит multichoice в вопрос с одним или несколькими правильными
+// вариантами; возвращает false, если вопрос пропущен
+func moodleChoice(result *ImportResult, index int, title string, mq moodleQuestion, q *models.CreateQuestionRequest) bool {
+	single := strings.TrimSpace(mq.Single) != "false" && strings.TrimSpace(mq.Single) != "0"
+	q.QuestionType = typeMultipleChoice
+	if single {
+		q.QuestionType = typeSingleChoice
+	}
+
+	partial, penalties := false, false
+	weights := map[string]bool{}
+	for _, a := range mq.Answers {
+		f := fraction(a.Fraction)
+		option := models.CreateAnswerOptionRequest{OptionText: moodleTextValue(a.Format, a.Text)}
+		switch {
+		case single:
+			option.IsCorrect = f >= 100
+			partial = partial || (f > 0 && f < 100)
+		default:
+			option.IsCorrect = f > 0
+			penalties = penalties || f < 0
+			if f > 0 {
+				weights[percent(f)] = true
+			}
+		}
+		q.Options = append(q.Options, option)
+	}
+
+	if len(q.Options) == 0 {
+		result.skip(index, title, "question has no answers")
+		return false
+	}
+	if partial {
+		result.warn(index, title, "partially correct options are not supported; they are treated as incorrect")
+	}
+	if penalties {
+		result.warn(index, title, "negative grades for wrong options are not supported; wrong options give 0")
+	}
+	if len(weights) > 1 {
+		result.warn(index, title, "correct options have different weights; they are weighted equally")
+	}
+	return true
+}
+
+// moodleTextValue возвращает текст без HTML, если формат его допускает
+func moodleTextValue(format, text string) string {
+	switch format {
+	case "plain_text", "markdown":
+		return strings.TrimSpace(text)
+	default:
+		// По умолчанию Moodle хранит текст в html
+		return stripHTML(text)
+	}
+}
+
+func fraction(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f
+}
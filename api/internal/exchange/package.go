@@ -0,0 +1,260 @@
+package exchange
+
+import (
+	"api/internal/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Собственный формат пакета теста — полная копия теста в JSON для переноса
+// между экземплярами (например, со стенда на рабочий сервер). Вывод детерминирован:
+// порядок полей фиксирован, элементы идут в порядке position, служебные id
+// экземпляра не пишутся. Поэтому экспорт импортированного пакета дает тот же файл
+// байт в байт. Вложений у вопросов нет, так что пакет — один JSON-файл.
+const (
+	PackageFormat  = "obuchaushchee-test-package"
+	PackageVersion = 1
+)
+
+type Package struct {
+	Format  string      `json:"format"`
+	Version int         `json:"version"`
+	Test    PackageTest `json:"test"`
+}
+
+type PackageTest struct {
+	ExternalID string `json:"external_id"`
+	Title      string `json:"title"`
+	Duration   int    `json:"duration"` // в секундах
+	Attempts   int    `json:"attempts"`
+	// Срок сдачи в секундах от создания теста; null — без срока
+	EndDateOffset    *int64            `json:"end_date_offset"`
+	ShuffleQuestions bool              `json:"shuffle_questions"`
+	ShuffleOptions   bool              `json:"shuffle_options"`
+	Sections         []PackageSection  `json:"sections"`
+	Questions        []PackageQuestion `json:"questions"`
+}
+
+type PackageSection struct {
+	Title     string `json:"title"`
+	DrawCount int    `json:"draw_count"`
+}
+
+type PackageQuestion struct {
+	Type        string `json:"type"`
+	Text        string `json:"text"`
+	Points      int    `json:"points"`
+	ScoringMode string `json:"scoring_mode"`
+	Section     int    `json:"section,omitempty"` // номер раздела в sections с 1
+	// Вопросы банка курса переносятся копией: банк другого экземпляра недоступен
+	Options     []PackageOption  `json:"options,omitempty"`
+	Pairs       []PackagePair    `json:"pairs,omitempty"`
+	AnswerRules []PackageRule    `json:"answer_rules,omitempty"`
+	Relation    *PackageRelation `json:"relation,omitempty"`
+}
+
+type PackageOption struct {
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+}
+
+type PackagePair struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+type PackageRule struct {
+	Type      string  `json:"type"`
+	Pattern   string  `json:"pattern"`
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+// Задание вопроса об отношении: ожидаемые пары, условия на свойства
+// или вид и размер генерируемого упражнения
+type PackageRelation struct {
+	Elements  []string              `json:"elements"`
+	Mode      string                `json:"mode"`
+	Pairs     []PackageRelationPair `json:"pairs,omitempty"`
+	Required  []string              `json:"required,omitempty"`
+	Forbidden []string              `json:"forbidden,omitempty"`
+	Exercise  string                `json:"exercise,omitempty"`
+	Size      int                   `json:"size,omitempty"`
+}
+
+type PackageRelationPair struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NewPackage собирает пакет из теста с ключом ответов
+func NewPackage(test *models.Test, questions []models.TeacherQuestion) *Package {
+	pkg := &Package{
+		Format:  PackageFormat,
+		Version: PackageVersion,
+		Test: PackageTest{
+			ExternalID:       test.ExternalID,
+			Title:            test.Title,
+			Duration:         test.Duration,
+			Attempts:         test.Attempts,
+			ShuffleQuestions: test.ShuffleQuestions,
+			ShuffleOptions:   test.ShuffleOptions,
+			Sections:         []PackageSection{},
+			Questions:        []PackageQuestion{},
+		},
+	}
+
+	if !test.EndDate.IsZero() {
+		offset := int64(test.EndDate.Sub(test.UploadDate).Round(time.Second) / time.Second)
+		pkg.Test.EndDateOffset = &offset
+	}
+
+	sectionNumbers := make(map[int]int, len(test.Sections))
+	for i, section := range test.Sections {
+		sectionNumbers[section.ID] = i + 1
+		pkg.Test.Sections = append(pkg.Test.Sections, PackageSection{Title: section.Title, DrawCount: section.DrawCount})
+	}
+
+	for _, q := range questions {
+		pq := PackageQuestion{
+			Type:        q.QuestionType,
+			Text:        q.QuestionText,
+			Points:      q.Points,
+			ScoringMode: q.ScoringMode,
+		}
+		if pq.ScoringMode == "" {
+			pq.ScoringMode = "all_or_nothing"
+		}
+		if q.SectionID != nil {
+			pq.Section = sectionNumbers[*q.SectionID]
+		}
+		for _, o := range q.Options {
+			pq.Options = append(pq.Options, PackageOption{Text: o.OptionText, Correct: o.IsCorrect})
+		}
+		for _, p := range q.Pairs {
+			pq.Pairs = append(pq.Pairs, PackagePair{Left: p.LeftText, Right: p.RightText})
+		}
+		for _, r := range q.AnswerRules {
+			pq.AnswerRules = append(pq.AnswerRules, PackageRule{Type: r.RuleType, Pattern: r.Pattern, Tolerance: r.Tolerance})
+		}
+		if r := q.Relation; r != nil {
+			pq.Relation = &PackageRelation{
+				Elements: r.Elements, Mode: r.Mode, Required: r.Required, Forbidden: r.Forbidden,
+				Exercise: r.Exercise, Size: r.Size,
+			}
+			for _, p := range r.Pairs {
+				pq.Relation.Pairs = append(pq.Relation.Pairs, PackageRelationPair{From: p.From, To: p.To})
+			}
+		}
+		pkg.Test.Questions = append(pkg.Test.Questions, pq)
+	}
+
+	return pkg
+}
+
+// Marshal возвращает каноническое представление пакета
+func (p *Package) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(p); err != nil {
+		return nil, fmt.Errorf("failed to encode test package: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParsePackage разбирает пакет; неизвестные поля и более новые версии формата отклоняются,
+// чтобы при импорте ничего не терялось молча
+func ParsePackage(data []byte) (*Package, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var pkg Package
+	if err := dec.Decode(&pkg); err != nil {
+		return nil, fmt.Errorf("invalid test package: %w", err)
+	}
+	if pkg.Format != PackageFormat {
+		return nil, fmt.Errorf("unknown package format %q", pkg.Format)
+	}
+	if pkg.Version < 1 || pkg.Version > PackageVersion {
+		return nil, fmt.Errorf("unsupported package version %d", pkg.Version)
+	}
+	if pkg.Test.ExternalID == "" {
+		return nil, fmt.Errorf("package has no external_id")
+	}
+
+	// Пустые списки пишутся как [], чтобы повторный вывод совпадал с исходным
+	pkg.Version = PackageVersion
+	if pkg.Test.Sections == nil {
+		pkg.Test.Sections = []PackageSection{}
+	}
+	if pkg.Test.Questions == nil {
+		pkg.Test.Questions = []PackageQuestion{}
+	}
+
+	return &pkg, nil
+}
+
+// CreateRequest переводит пакет в запрос создания теста без курса и срока сдачи
+func (p *Package) CreateRequest() models.CreateTestRequest {
+	req := models.CreateTestRequest{
+		Title:            p.Test.Title,
+		Duration:         p.Test.Duration,
+		Attempts:         p.Test.Attempts,
+		ShuffleQuestions: p.Test.ShuffleQuestions,
+		ShuffleOptions:   p.Test.ShuffleOptions,
+		ExternalID:       p.Test.ExternalID,
+		EndDateOffset:    p.Test.EndDateOffset,
+	}
+
+	for i, s := range p.Test.Sections {
+		req.Sections = append(req.Sections, models.CreateSectionRequest{
+			Title:     s.Title,
+			DrawCount: s.DrawCount,
+			Position:  i + 1,
+		})
+	}
+
+	for i, pq := range p.Test.Questions {
+		q := models.CreateQuestionRequest{
+			QuestionText: pq.Text,
+			QuestionType: pq.Type,
+			Points:       pq.Points,
+			Position:     i + 1,
+			ScoringMode:  pq.ScoringMode,
+			Section:      pq.Section,
+		}
+		for _, o := range pq.Options {
+			q.Options = append(q.Options, models.CreateAnswerOptionRequest{OptionText: o.Text, IsCorrect: o.Correct})
+		}
+		for _, pair := range pq.Pairs {
+			q.Pairs = append(q.Pairs, models.CreateMatchingPairRequest{LeftText: pair.Left, RightText: pair.Right})
+		}
+		for _, r := range pq.AnswerRules {
+			q.AnswerRules = append(q.AnswerRules, models.CreateTextAnswerRuleRequest{RuleType: r.Type, Pattern: r.Pattern, Tolerance: r.Tolerance})
+		}
+		if r := pq.Relation; r != nil {
+			q.Relation = &models.RelationSpec{
+				Elements: r.Elements, Mode: r.Mode, Required: r.Required, Forbidden: r.Forbidden,
+				Exercise: r.Exercise, Size: r.Size,
+			}
+			for _, p := range r.Pairs {
+				q.Relation.Pairs = append(q.Relation.Pairs, models.RelationPair{From: p.From, To: p.To})
+			}
+		}
+		numberPositions(&q)
+		req.Questions = append(req.Questions, q)
+	}
+
+	return req
+}
+
+// EndDate возвращает срок сдачи для теста, созданного в момент created
+func (p *Package) EndDate(created time.Time) time.Time {
+	if p.Test.EndDateOffset == nil {
+		return time.Time{}
+	}
+	return created.Add(time.Duration(*p.Test.EndDateOffset) * time.Second)
+}
@@ -0,0 +1,266 @@
+// Пакет exercise генерирует упражнения по бинарным отношениям: случайное
+// отношение нужного вида, формулировку задания и ключ ответа. Упражнение
+// полностью определяется видом, размером множества и зерном, поэтому
+// вариант попытки восстанавливается при проверке без хранения в БД.
+package exercise
+
+import (
+	"api/internal/relation"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Kind — вид упражнения
+type Kind string
+
+const (
+	KindProperties         Kind = "properties"          // определить свойства отношения
+	KindClosure            Kind = "closure"             // построить замыкание
+	KindEquivalenceClasses Kind = "equivalence_classes" // найти классы эквивалентности
+	KindHasse              Kind = "hasse"               // построить диаграмму Хассе
+	KindComposition        Kind = "composition"         // найти композицию R∘S
+)
+
+// Kinds — все виды упражнений в порядке вывода
+var Kinds = []Kind{KindProperties, KindClosure, KindEquivalenceClasses, KindHasse, KindComposition}
+
+func ParseKind(s string) (Kind, error) {
+	for _, k := range Kinds {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown exercise kind %q", s)
+}
+
+// Форма ответа, которую ждет упражнение
+const (
+	AnswerPairs      = "pairs"      // отношение парами или матрицей
+	AnswerProperties = "properties" // список свойств, которыми обладает отношение
+	AnswerClasses    = "classes"    // разбиение множества на классы
+)
+
+// Границы размера множества: меньше двух элементов упражнение вырождено,
+// больше восьми — ответ неудобно вводить вручную
+const (
+	MinSize     = 2
+	MaxSize     = 8
+	DefaultSize = 4
+)
+
+var (
+	ErrInvalidSize = fmt.Errorf("exercise set size must be between %d and %d", MinSize, MaxSize)
+	ErrBadAnswer   = errors.New("answer does not match the exercise")
+)
+
+// Given — отношение из условия задания
+type Given struct {
+	Name     string             `json:"name"`
+	Relation *relation.Relation `json:"relation"`
+}
+
+// Task — задание в том виде, в котором его видит студент (без ключа)
+type Task struct {
+	Kind      Kind     `json:"kind"`
+	Seed      int64    `json:"seed"`
+	Prompt    string   `json:"prompt"`
+	Elements  []string `json:"elements"`
+	Relations []Given  `json:"relations"`
+	Answer    string   `json:"answer"` // pairs, properties, classes
+}
+
+// Key — ключ ответа; заполнено поле, соответствующее Task.Answer
+type Key struct {
+	Relation   *relation.Relation  `json:"relation,omitempty"`
+	Properties []relation.Property `json:"properties,omitempty"`
+	Classes    [][]string          `json:"classes,omitempty"`
+}
+
+// Exercise — задание вместе с ключом ответа
+type Exercise struct {
+	Task
+	Key Key `json:"key"`
+}
+
+// Response — ответ студента; используется поле, соответствующее Task.Answer
+type Response struct {
+	Relation   *relation.Relation
+	Properties []string
+	Classes    [][]string
+}
+
+// Generate строит упражнение вида kind на множестве из size элементов.
+// Одинаковые вид, размер и зерно всегда дают одно и то же упражнение.
+func Generate(kind Kind, size int, seed int64) (*Exercise, error) {
+	if size < MinSize || size > MaxSize {
+		return nil, ErrInvalidSize
+	}
+
+	names := Elements(size)
+	set, _ := relation.NewSet(names...)
+
+	rng := rand.New(rand.NewSource(seed))
+	ex := &Exercise{Task: Task{Kind: kind, Seed: seed, Elements: names}}
+
+	switch kind {
+	case KindProperties:
+		r := randomStructured(set, rng)
+		ex.given("R", r)
+		ex.Answer = AnswerProperties
+		ex.Prompt = fmt.Sprintf("Дано отношение R на множестве A = %s:\nR = %s.\n"+
+			"Определите, какими свойствами обладает R: рефлексивность, иррефлексивность, "+
+			"симметричность, антисимметричность, асимметричность, транзитивность, полнота.", set, r)
+		ex.Key.Properties = []relation.Property{}
+		for _, p := range relation.Properties {
+			if r.Satisfies(p) {
+				ex.Key.Properties = append(ex.Key.Properties, p)
+			}
+		}
+
+	case KindClosure:
+		r := randomRelation(set, rng, 0.3)
+		op := closures[rng.Intn(len(closures))]
+		result, _ := relation.Apply(op.op, r, nil, 0)
+		ex.given("R", r)
+		ex.Answer = AnswerPairs
+		ex.Prompt = fmt.Sprintf("Дано отношение R на множестве A = %s:\nR = %s.\nПостройте %s отношения R.", set, r, op.title)
+		ex.Key.Relation = result.Relation
+
+	case KindEquivalenceClasses:
+		classes := randomPartition(names, rng)
+		r, _ := relation.FromPartition(set, classes)
+		ex.given("R", r)
+		ex.Answer = AnswerClasses
+		ex.Prompt = fmt.Sprintf("Дано отношение эквивалентности R на множестве A = %s:\nR = %s.\n"+
+			"Найдите классы эквивалентности (фактор-множество A/R).", set, r)
+		ex.Key.Classes = classes
+
+	case KindHasse:
+		r := randomPartialOrder(set, rng)
+		ex.given("R", r)
+		ex.Answer = AnswerPairs
+		ex.Prompt = fmt.Sprintf("Дано отношение частичного порядка R на множестве A = %s:\nR = %s.\n"+
+			"Постройте диаграмму Хассе: укажите все пары (x, y), в которых y покрывает x.", set, r)
+		ex.Key.Relation, _ = r.Covering()
+
+	case KindComposition:
+		r := randomRelation(set, rng, 0.3)
+		s := randomRelation(set, rng, 0.3)
+		result, _ := relation.Compose(r, s)
+		ex.given("R", r)
+		ex.given("S", s)
+		ex.Answer = AnswerPairs
+		ex.Prompt = fmt.Sprintf("Даны отношения на множестве A = %s:\nR = %s,\nS = %s.\n"+
+			"Найдите композицию R∘S = {(x, z) | xRy и ySz для некоторого y}.", set, r, s)
+		ex.Key.Relation = result.Relation
+
+	default:
+		return nil, fmt.Errorf("unknown exercise kind %q", kind)
+	}
+
+	return ex, nil
+}
+
+// Elements возвращает элементы множества упражнения: первые size латинских букв
+func Elements(size int) []string {
+	names := make([]string, size)
+	for i := range names {
+		names[i] = string(rune('a' + i))
+	}
+	return names
+}
+
+func (ex *Exercise) given(name string, r *relation.Relation) {
+	ex.Relations = append(ex.Relations, Given{Name: name, Relation: r})
+}
+
+// Замыкания, из которых выбирается упражнение KindClosure
+var closures = []struct {
+	op    relation.Operation
+	title string
+}{
+	{relation.OpReflexiveClosure, "рефлексивное замыкание"},
+	{relation.OpSymmetricClosure, "симметричное замыкание"},
+	{relation.OpTransitiveClosure, "транзитивное замыкание"},
+	{relation.OpEquivalenceClosure, "эквивалентное замыкание (наименьшую эквивалентность, содержащую R)"},
+}
+
+// Score сравнивает ответ с ключом и возвращает число верных элементов ответа
+// из общего числа: для отношения — общие пары из всех пар ответа и ключа,
+// для свойств — верно определенные свойства из всех, для разбиения —
+// совпавшие классы из наибольшего числа классов ответа и ключа
+func (ex *Exercise) Score(resp Response) (int, int, error) {
+	switch ex.Answer {
+	case AnswerPairs:
+		if resp.Relation == nil {
+			return 0, 0, fmt.Errorf("%w: pairs are required", ErrBadAnswer)
+		}
+		correct := 0
+		for _, p := range ex.Key.Relation.Pairs() {
+			if resp.Relation.Has(p.From, p.To) {
+				correct++
+			}
+		}
+		total := ex.Key.Relation.Len() + resp.Relation.Len() - correct
+		if total == 0 {
+			return 1, 1, nil
+		}
+		return correct, total, nil
+
+	case AnswerProperties:
+		claimed := make(map[relation.Property]bool, len(resp.Properties))
+		for _, name := range resp.Properties {
+			p, err := relation.ParseProperty(strings.TrimSpace(name))
+			if err != nil {
+				return 0, 0, fmt.Errorf("%w: %v", ErrBadAnswer, err)
+			}
+			claimed[p] = true
+		}
+		holds := make(map[relation.Property]bool, len(ex.Key.Properties))
+		for _, p := range ex.Key.Properties {
+			holds[p] = true
+		}
+		correct := 0
+		for _, p := range relation.Properties {
+			if claimed[p] == holds[p] {
+				correct++
+			}
+		}
+		return correct, len(relation.Properties), nil
+
+	case AnswerClasses:
+		set, _ := relation.NewSet(ex.Elements...)
+		classes, err := relation.NormalizePartition(set, resp.Classes)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: %v", ErrBadAnswer, err)
+		}
+		return relation.CommonBlocks(ex.Key.Classes, classes), max(len(classes), len(ex.Key.Classes)), nil
+
+	default:
+		return 0, 0, fmt.Errorf("unknown exercise answer %q", ex.Answer)
+	}
+}
+
+// Misjudged возвращает свойства, которые студент определил неверно
+func (ex *Exercise) Misjudged(properties []string) []relation.Property {
+	claimed := make(map[relation.Property]bool, len(properties))
+	for _, name := range properties {
+		if p, err := relation.ParseProperty(strings.TrimSpace(name)); err == nil {
+			claimed[p] = true
+		}
+	}
+	holds := make(map[relation.Property]bool, len(ex.Key.Properties))
+	for _, p := range ex.Key.Properties {
+		holds[p] = true
+	}
+
+	var wrong []relation.Property
+	for _, p := range relation.Properties {
+		if claimed[p] != holds[p] {
+			wrong = append(wrong, p)
+		}
+	}
+	return wrong
+}
@@ -0,0 +1,104 @@
+package exercise
+
+import (
+	"api/internal/relation"
+	"math/rand"
+	"sort"
+)
+
+// randomRelation заполняет матрицу независимо с вероятностью density
+func randomRelation(set *relation.Set, rng *rand.Rand, density float64) *relation.Relation {
+	n := set.Len()
+	matrix := make([][]bool, n)
+	for i := range matrix {
+		matrix[i] = make([]bool, n)
+		for j := range matrix[i] {
+			matrix[i][j] = rng.Float64() < density
+		}
+	}
+	r, _ := relation.FromMatrix(set, matrix)
+	return r
+}
+
+// randomStructured строит отношение для упражнения на свойства. Совсем
+// случайное отношение почти никогда не бывает транзитивным или симметричным,
+// поэтому за основу берется эквивалентность, порядок или симметричное
+// отношение, а затем с вероятностью 1/2 одна пара переключается, чтобы
+// в ответе встречались и «почти выполненные» свойства.
+func randomStructured(set *relation.Set, rng *rand.Rand) *relation.Relation {
+	var r *relation.Relation
+	switch rng.Intn(5) {
+	case 0:
+		r, _ = relation.FromPartition(set, randomPartition(set.Elements(), rng))
+	case 1:
+		r = randomPartialOrder(set, rng)
+	case 2:
+		r = strictPart(randomPartialOrder(set, rng))
+	case 3:
+		r = relation.SymmetricClosure(randomRelation(set, rng, 0.25)).Relation
+	default:
+		r = randomRelation(set, rng, 0.35)
+	}
+
+	if rng.Intn(2) == 0 {
+		a, b := set.Element(rng.Intn(set.Len())), set.Element(rng.Intn(set.Len()))
+		if r.Has(a, b) {
+			_ = r.Remove(a, b)
+		} else {
+			_ = r.Add(a, b)
+		}
+	}
+	return r
+}
+
+// randomPartition разбивает элементы на случайное число непустых классов.
+// Классы упорядочены по первому элементу, элементы — по порядку множества.
+func randomPartition(elements []string, rng *rand.Rand) [][]string {
+	k := 1 + rng.Intn(len(elements))
+	blocks := make([][]string, k)
+	// Первые k элементов в случайном порядке открывают свои классы,
+	// чтобы ни один класс не остался пустым
+	order := rng.Perm(len(elements))
+	for pos, i := range order {
+		block := pos
+		if pos >= k {
+			block = rng.Intn(k)
+		}
+		blocks[block] = append(blocks[block], elements[i])
+	}
+
+	index := make(map[string]int, len(elements))
+	for i, e := range elements {
+		index[e] = i
+	}
+	for _, class := range blocks {
+		sort.Slice(class, func(i, j int) bool { return index[class[i]] < index[class[j]] })
+	}
+	sort.Slice(blocks, func(i, j int) bool { return index[blocks[i][0]] < index[blocks[j][0]] })
+	return blocks
+}
+
+// randomPartialOrder берет случайный линейный порядок элементов, оставляет
+// часть пар «меньший — больший» и замыкает результат рефлексивно и транзитивно
+func randomPartialOrder(set *relation.Set, rng *rand.Rand) *relation.Relation {
+	order := rng.Perm(set.Len())
+	r := relation.New(set)
+	for i := range order {
+		for j := i + 1; j < len(order); j++ {
+			if rng.Float64() < 0.35 {
+				_ = r.Add(set.Element(order[i]), set.Element(order[j]))
+			}
+		}
+	}
+	r = relation.TransitiveClosure(r).Relation
+	return relation.ReflexiveClosure(r).Relation
+}
+
+// strictPart убирает из отношения петли
+func strictPart(r *relation.Relation) *relation.Relation {
+	strict := r.Clone()
+	for _, e := range strict.Set().Elements() {
+		_ = strict.Remove(e, e)
+	}
+	return strict
+}
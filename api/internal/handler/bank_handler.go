@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"api/internal/models"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetCourseBanks возвращает банки вопросов курса (GET ?course_id=, токен в Authorization)
+func (h *TestHandler) GetCourseBanks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	courseID, err := strconv.Atoi(r.URL.Query().Get("course_id"))
+	if err != nil {
+		http.Error(w, "Некорректный ID курса", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), bearerToken(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	banks, err := h.service.GetCourseBanks(r.Context(), user.Id, courseID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(banks)
+}
+
+// GetBank возвращает банк с вопросами (GET, фильтры ?tag= и ?difficulty=)
+func (h *TestHandler) GetBank(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bankID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID банка", http.StatusBadRequest)
+		return
+	}
+
+	filter := models.BankQuestionFilter{Tag: r.URL.Query().Get("tag")}
+	if d := r.URL.Query().Get("difficulty"); d != "" {
+		filter.Difficulty, err = strconv.Atoi(d)
+		if err != nil {
+			http.Error(w, "Некорректная сложность", http.StatusBadRequest)
+			return
+		}
+	}
+
+	user, err := h.currentTeacher(r.Context(), bearerToken(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	bank, err := h.service.GetBank(r.Context(), user.Id, bankID, filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bank)
+}
+
+func (h *TestHandler) CreateBank(w http.ResponseWriter, r *http.Request) {
+	h.handleBankRequest(w, r, func(userID int, req *models.BankRequest) (any, error) {
+		return h.service.CreateBank(r.Context(), userID, req)
+	})
+}
+
+func (h *TestHandler) UpdateBank(w http.ResponseWriter, r *http.Request) {
+	h.handleBankRequest(w, r, func(userID int, req *models.BankRequest) (any, error) {
+		return h.service.UpdateBank(r.Context(), userID, req)
+	})
+}
+
+func (h *TestHandler) DeleteBank(w http.ResponseWriter, r *http.Request) {
+	h.handleBankRequest(w, r, func(userID int, req *models.BankRequest) (any, error) {
+		return map[string]string{"status": "deleted"}, h.service.DeleteBank(r.Context(), userID, req.BankID)
+	})
+}
+
+func (h *TestHandler) CreateBankQuestion(w http.ResponseWriter, r *http.Request) {
+	h.handleBankQuestionRequest(w, r, func(userID int, req *models.BankQuestionRequest) (any, error) {
+		return h.service.CreateBankQuestion(r.Context(), userID, req)
+	})
+}
+
+func (h *TestHandler) UpdateBankQuestion(w http.ResponseWriter, r *http.Request) {
+	h.handleBankQuestionRequest(w, r, func(userID int, req *models.BankQuestionRequest) (any, error) {
+		return h.service.UpdateBankQuestion(r.Context(), userID, req)
+	})
+}
+
+func (h *TestHandler) DeleteBankQuestion(w http.ResponseWriter, r *http.Request) {
+	h.handleBankQuestionRequest(w, r, func(userID int, req *models.BankQuestionRequest) (any, error) {
+		return map[string]string{"status": "deleted"}, h.service.DeleteBankQuestion(r.Context(), userID, req.QuestionID)
+	})
+}
+
+// handleBankRequest разбирает POST-запрос по банку, проверяет преподавателя и пишет ответ
+func (h *TestHandler) handleBankRequest(w http.ResponseWriter, r *http.Request, action func(userID int, req *models.BankRequest) (any, error)) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.BankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	result, err := action(user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleBankQuestionRequest — то же для запросов по вопросам банка
+func (h *TestHandler) handleBankQuestionRequest(w http.ResponseWriter, r *http.Request, action func(userID int, req *models.BankQuestionRequest) (any, error)) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.BankQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	result, err := action(user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
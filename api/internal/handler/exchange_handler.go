@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"api/internal/exchange"
+	"api/internal/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ExportTest отдает тест файлом Moodle XML или GIFT (GET ?format=moodle|gift, токен в Authorization)
+func (h *TestHandler) ExportTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	testID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID теста", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exchange.FormatMoodleXML
+	}
+
+	user, err := h.currentTeacher(r.Context(), bearerToken(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	data, err := h.service.ExportTest(r.Context(), user.Id, testID, format)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", exchange.ContentType(format))
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="test-%d%s"`, testID, exchange.FileExtension(format)))
+	w.Write(data)
+}
+
+// ImportTest создает тест из файла Moodle XML или GIFT; при dry_run только возвращает отчет
+func (h *TestHandler) ImportTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ImportTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	report, err := h.service.ImportTest(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.DryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// ExportPackage отдает тест пакетом в собственном формате JSON (GET, токен в Authorization)
+func (h *TestHandler) ExportPackage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	testID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID теста", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), bearerToken(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	data, err := h.service.ExportPackage(r.Context(), user.Id, testID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="test-%d.json"`, testID))
+	w.Write(data)
+}
+
+// ImportPackage создает или обновляет тест из пакета; повторный импорт ничего не меняет
+func (h *TestHandler) ImportPackage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ImportPackageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	result, err := h.service.ImportPackage(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if result.Status == "created" {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"api/internal/models"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// GetGradingQueue возвращает преподавателю ответы, ожидающие ручной проверки
+func (h *TestHandler) GetGradingQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request := struct {
+		Token string `json:"token"`
+	}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentUser(r.Context(), request.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if user.Role != "teacher" {
+		http.Error(w, "Пользователь не является преподавателем", http.StatusForbidden)
+		return
+	}
+
+	answers, err := h.service.GetGradingQueue(r.Context(), user.Id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(answers)
+}
+
+// GradeAnswer выставляет баллы и комментарий за ответ
+func (h *TestHandler) GradeAnswer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.GradeAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentUser(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if user.Role != "teacher" {
+		http.Error(w, "Пользователь не является преподавателем", http.StatusForbidden)
+		return
+	}
+
+	attempt, err := h.service.GradeAnswer(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attempt)
+}
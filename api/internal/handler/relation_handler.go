@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"api/internal/models"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ExplainRelation проверяет свойства отношения и объясняет результат с контрпримером.
+// Авторизация не нужна: тренажер доступен без входа, а вычисление не обращается к БД.
+func (h *TestHandler) ExplainRelation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ExplainRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ExplainRelation(&req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// EquivalenceClasses находит фактор-множество отношения или строит отношение
+// эквивалентности по разбиению. Как и ExplainRelation, доступен без входа.
+func (h *TestHandler) EquivalenceClasses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.EquivalenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.EquivalenceClasses(&req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AnalyzePoset анализирует частичный порядок для тренажера; доступен без входа
+func (h *TestHandler) AnalyzePoset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.PosetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.AnalyzePoset(&req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ParseRelation разбирает текстовую запись отношения (пары, матрица, список
+// смежности или условие на отрезке целых) для импорта в тренажер
+func (h *TestHandler) ParseRelation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ParseRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ParseRelation(&req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RenderRelation отдает изображение отношения в SVG (GET, без входа):
+// ?view=graph|matrix|hasse&elements=a,b,c&matrix=110,011,001 — строки матрицы
+// через запятую. Изображение зависит только от отношения, поэтому ответ
+// кэшируется браузером и проверяется по ETag.
+func (h *TestHandler) RenderRelation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := models.RenderRelationRequest{View: query.Get("view"), Elements: strings.Split(query.Get("elements"), ",")}
+	if req.View == "" {
+		req.View = "graph"
+	}
+	for _, line := range strings.Split(query.Get("matrix"), ",") {
+		row := make([]int, 0, len(line))
+		for _, c := range strings.TrimSpace(line) {
+			if c != '0' && c != '1' {
+				http.Error(w, "Матрица должна состоять из 0 и 1", http.StatusBadRequest)
+				return
+			}
+			row = append(row, int(c-'0'))
+		}
+		req.Matrix = append(req.Matrix, row)
+	}
+
+	svg, key, err := h.service.RenderRelation(&req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	etag := `"` + key + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(svg)
+}
+
+// GenerateExercise строит упражнение по отношениям для предпросмотра
+// преподавателем: задание, ключ ответа и зерно, по которому его можно повторить
+func (h *TestHandler) GenerateExercise(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.GenerateExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.currentTeacher(r.Context(), req.Token); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	ex, err := h.service.GenerateExercise(&req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ex)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"api/internal/models"
+	"api/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+// currentUser проверяет access-токен и возвращает пользователя из БД
+func (h *TestHandler) currentUser(ctx context.Context, token string) (*models.User, error) {
+	username, err := service.GetUsernameFromToken(token)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+
+	user, err := h.service.Repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return nil, errUnauthorized
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// currentTeacher возвращает пользователя, если он преподаватель
+func (h *TestHandler) currentTeacher(ctx context.Context, token string) (*models.User, error) {
+	user, err := h.currentUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != "teacher" {
+		return nil, fmt.Errorf("%w: user is not a teacher", service.ErrForbidden)
+	}
+	return user, nil
+}
+
+// bearerToken достает токен из заголовка Authorization (с префиксом Bearer или без)
+func bearerToken(r *http.Request) string {
+	return strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+}
+
+// writeServiceError выбирает HTTP-статус по ошибке сервиса
+func writeServiceError(w http.ResponseWriter, err error) {
+	log.Println("Ошибка " + err.Error())
+
+	switch {
+	case errors.Is(err, errUnauthorized):
+		http.Error(w, "Пользователь не авторизован", http.StatusUnauthorized)
+	case errors.Is(err, service.ErrForbidden):
+		http.Error(w, "Доступ запрещен: "+err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "Не найдено: "+err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrConflict):
+		http.Error(w, "Конфликт: "+err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidInput):
+		http.Error(w, "Некорректный запрос: "+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -19,39 +19,88 @@ func NewTestHandler(s *service.TestService) *TestHandler {
 	return &TestHandler{service: s}
 }
 
+// GetTest возвращает тест в зависимости от роли вызывающего: преподавателю курса —
+// с ключом ответов, студенту — без признаков правильности.
+// Токен передается в заголовке Authorization.
 func (h *TestHandler) GetTest(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	testID := vars["id"]
 
 	log.Println("Получаем тест с id: " + testID)
 
-	test, questions, err := h.service.GetTest(r.Context(), testID)
+	user, err := h.currentUser(r.Context(), bearerToken(r))
 	if err != nil {
-		log.Println("Error getting test " + err.Error())
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeServiceError(w, err)
 		return
 	}
 
-	response := struct {
-		Test      *models.Test      `json:"test"`
-		Questions []models.Question `json:"questions"`
-	}{
-		Test:      test,
-		Questions: questions,
+	var response any
+	switch user.Role {
+	case "teacher":
+		test, questions, err := h.service.GetTeacherTest(r.Context(), user.Id, testID)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		response = struct {
+			Test      *models.Test             `json:"test"`
+			Questions []models.TeacherQuestion `json:"questions"`
+		}{Test: test, Questions: questions}
+	case "student":
+		test, questions, err := h.service.GetStudentTest(r.Context(), user.Id, testID)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		response = struct {
+			Test      *models.Test             `json:"test"`
+			Questions []models.StudentQuestion `json:"questions"`
+		}{Test: test, Questions: questions}
+	default:
+		http.Error(w, "Доступ запрещен", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ReleaseResults открывает или закрывает студентам просмотр теста после попыток
+func (h *TestHandler) ReleaseResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request := struct {
+		Token    string `json:"token"`
+		TestID   int    `json:"test_id"`
+		Released bool   `json:"released"`
+	}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
 	}
 
-	j, err := json.Marshal(response)
+	user, err := h.currentUser(r.Context(), request.Token)
 	if err != nil {
-		log.Println("Error getting test " + err.Error())
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeServiceError(w, err)
 		return
 	}
 
-	log.Println(string(j))
+	if user.Role != "teacher" {
+		http.Error(w, "Пользователь не является преподавателем", http.StatusForbidden)
+		return
+	}
+
+	if err := h.service.SetResultsReleased(r.Context(), user.Id, request.TestID, request.Released); err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
 }
 
 func (h *TestHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
@@ -67,29 +116,24 @@ func (h *TestHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	username := service.GetUsernameGromToken(infoStart.Token)
-
-	var UserID int
-	err = h.service.Repo.Db.QueryRow("SELECT id FROM users WHERE username = $1", username).Scan(&UserID)
+	user, err := h.currentUser(r.Context(), infoStart.Token)
 	if err != nil {
-		log.Println("Некорректный запрос " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
 	testId, err := strconv.Atoi(infoStart.Id)
 	if err != nil {
 		log.Println("Некорректный запрос(test_handler.StartAttempt) " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Некорректный ID теста", http.StatusBadRequest)
 		return
 	}
 
 	log.Println("Попытка начать тест с id: " + strconv.Itoa(testId))
 
-	attempt, err := h.service.StartAttempt(r.Context(), UserID, testId)
+	attempt, err := h.service.StartAttempt(r.Context(), user.Id, testId)
 	if err != nil {
-		log.Println("Некорректный запрос " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -98,18 +142,6 @@ func (h *TestHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TestHandler) SubmitAnswer(w http.ResponseWriter, r *http.Request) {
-	// attemptID, err := strconv.Atoi(chi.URLParam(r, "attempt_id"))
-	// if err != nil {
-	// 	http.Error(w, "Invalid attempt ID", http.StatusBadRequest)
-	// 	return
-	// }
-
-	// userID, err := strconv.Atoi(chi.URLParam(r, "iduser"))
-	// if err != nil {
-	// 	http.Error(w, "Invalid test ID", http.StatusBadRequest)
-	// 	return
-	// }
-
 	answerData := struct {
 		Answer models.UserAnswer `json:"answer"`
 		Token  string            `json:"token"`
@@ -122,56 +154,100 @@ func (h *TestHandler) SubmitAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	username := service.GetUsernameGromToken(answerData.Token)
-
-	var UserID int
-	err = h.service.Repo.Db.QueryRow("SELECT id FROM users WHERE username = $1", username).Scan(&UserID)
+	user, err := h.currentUser(r.Context(), answerData.Token)
 	if err != nil {
-		log.Println("Некорректный запрос " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
-	if err := h.service.SubmitAnswer(r.Context(), UserID, &answerData.Answer); err != nil {
-		log.Println("Некорректный запрос " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.service.SubmitAnswer(r.Context(), user.Id, &answerData.Answer); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *TestHandler) FinishAttempt(w http.ResponseWriter, r *http.Request) {
-	answerData := struct {
-		Token  string `json:"token"`
-		TestID int    `json:"test_id"`
+// GetAttemptProgress возвращает текущую попытку и сохраненные ответы
+func (h *TestHandler) GetAttemptProgress(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		Token     string `json:"token"`
+		TestID    int    `json:"test_id"`
+		AttemptID int    `json:"attempt_id"`
 	}{}
 
-	err := json.NewDecoder(r.Body).Decode(&answerData)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentUser(r.Context(), request.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	progress, err := h.service.GetAttemptProgress(r.Context(), user.Id, request.TestID, request.AttemptID)
 	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
+// GetAttemptReview возвращает разбор завершенной попытки с баллами и разбором ошибок
+func (h *TestHandler) GetAttemptReview(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		Token     string `json:"token"`
+		AttemptID int    `json:"attempt_id"`
+	}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
 		return
 	}
 
-	username := service.GetUsernameGromToken(answerData.Token)
+	user, err := h.currentUser(r.Context(), request.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
-	var UserID int
-	err = h.service.Repo.Db.QueryRow("SELECT id FROM users WHERE username = $1", username).Scan(&UserID)
+	review, err := h.service.GetAttemptReview(r.Context(), user.Id, request.AttemptID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
-	var AttemptId int
-	err = h.service.Repo.Db.QueryRow("SELECT id FROM test_attempts WHERE user_id = $1 AND status = 'in_progress'", UserID).Scan(&AttemptId)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+func (h *TestHandler) FinishAttempt(w http.ResponseWriter, r *http.Request) {
+	// attempt_id необязателен: если его нет, завершается текущая попытка по test_id
+	answerData := struct {
+		Token     string `json:"token"`
+		TestID    int    `json:"test_id"`
+		AttemptID int    `json:"attempt_id"`
+	}{}
+
+	err := json.NewDecoder(r.Body).Decode(&answerData)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
 		return
 	}
 
-	attempt, err := h.service.FinishAttempt(r.Context(), UserID, AttemptId)
+	user, err := h.currentUser(r.Context(), answerData.Token)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
+		return
+	}
+
+	attempt, err := h.service.FinishAttempt(r.Context(), user.Id, answerData.TestID, answerData.AttemptID)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -193,10 +269,15 @@ func (h *TestHandler) CreateTest(w http.ResponseWriter, r *http.Request) {
 	//     return
 	// }
 
-	test, err := h.service.CreateTest(r.Context(), &req)
+	user, err := h.currentTeacher(r.Context(), req.Token)
 	if err != nil {
-		log.Println("Ошибка " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
+		return
+	}
+
+	test, err := h.service.CreateTest(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -204,3 +285,66 @@ func (h *TestHandler) CreateTest(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(test)
 }
+
+// UpdateTest заменяет настройки и вопросы теста (POST, тело как у CreateTest плюс test_id)
+func (h *TestHandler) UpdateTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	test, err := h.service.UpdateTest(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(test)
+}
+
+// DeleteTest удаляет тест (POST {token, test_id})
+func (h *TestHandler) DeleteTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request := struct {
+		Token  string `json:"token"`
+		TestID int    `json:"test_id"`
+	}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), request.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if err := h.service.DeleteTest(r.Context(), user.Id, request.TestID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
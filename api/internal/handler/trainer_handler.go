@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"api/internal/models"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// SaveTrainerRelation сохраняет отношение, построенное в тренажере, под именем
+func (h *TestHandler) SaveTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	h.handleTrainerRelationRequest(w, r, func(userID int, req *models.TrainerRelationRequest) (any, error) {
+		return h.service.SaveTrainerRelation(r.Context(), userID, req)
+	})
+}
+
+// GetTrainerRelations возвращает сохраненные отношения пользователя
+func (h *TestHandler) GetTrainerRelations(w http.ResponseWriter, r *http.Request) {
+	h.handleTrainerRelationRequest(w, r, func(userID int, req *models.TrainerRelationRequest) (any, error) {
+		return h.service.GetTrainerRelations(r.Context(), userID)
+	})
+}
+
+// GetTrainerRelation загружает сохраненное отношение по id
+func (h *TestHandler) GetTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	h.handleTrainerRelationRequest(w, r, func(userID int, req *models.TrainerRelationRequest) (any, error) {
+		return h.service.GetTrainerRelation(r.Context(), userID, req.ID)
+	})
+}
+
+func (h *TestHandler) DeleteTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	h.handleTrainerRelationRequest(w, r, func(userID int, req *models.TrainerRelationRequest) (any, error) {
+		return map[string]string{"status": "deleted"}, h.service.DeleteTrainerRelation(r.Context(), userID, req.ID)
+	})
+}
+
+// handleTrainerRelationRequest разбирает POST-запрос по отношениям тренажера,
+// проверяет пользователя и пишет ответ
+func (h *TestHandler) handleTrainerRelationRequest(w http.ResponseWriter, r *http.Request, action func(userID int, req *models.TrainerRelationRequest) (any, error)) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TrainerRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentUser(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	result, err := action(user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// CheckTrainerRelation объясняет свойства отношения и записывает проверку
+// в журнал практики пользователя
+func (h *TestHandler) CheckTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TrainerCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentUser(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response, err := h.service.CheckTrainerRelation(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetTrainerActivity возвращает преподавателю практику группы в тренажере:
+// время, число исследованных отношений и проверок свойств, результаты тестов
+func (h *TestHandler) GetTrainerActivity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TrainerActivityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentTeacher(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	activity, err := h.service.GetTrainerActivity(r.Context(), user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activity)
+}
+
+// GetTrainerTasks возвращает задания тренажера уровня level с прогрессом студента
+func (h *TestHandler) GetTrainerTasks(w http.ResponseWriter, r *http.Request) {
+	h.handleTrainerTaskRequest(w, r, func(userID int, req *models.TrainerTaskRequest) (any, error) {
+		return h.service.GetTrainerTasks(r.Context(), userID, req.Level)
+	})
+}
+
+// VerifyTrainerTask проверяет построенное студентом отношение: успех или
+// нарушенные условия с контрпримерами. Попытка засчитывается студенту.
+func (h *TestHandler) VerifyTrainerTask(w http.ResponseWriter, r *http.Request) {
+	h.handleTrainerTaskRequest(w, r, func(userID int, req *models.TrainerTaskRequest) (any, error) {
+		return h.service.VerifyTrainerTask(r.Context(), userID, req)
+	})
+}
+
+// handleTrainerTaskRequest — то же, что handleTrainerRelationRequest, для заданий тренажера
+func (h *TestHandler) handleTrainerTaskRequest(w http.ResponseWriter, r *http.Request, action func(userID int, req *models.TrainerTaskRequest) (any, error)) {
+	if r.Method != "POST" {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TrainerTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Println("Некорректный запрос " + err.Error())
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.currentUser(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	result, err := action(user.Id, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
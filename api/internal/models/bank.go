@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Банк вопросов курса
+type QuestionBank struct {
+	ID        int            `json:"id"`
+	CourseID  int            `json:"course_id"`
+	OwnerID   int            `json:"owner_id"`
+	Title     string         `json:"title"`
+	Shared    bool           `json:"shared"` // доступен остальным преподавателям курса
+	CreatedAt time.Time      `json:"created_at"`
+	Questions []BankQuestion `json:"questions,omitempty"`
+}
+
+// Вопрос банка с ключом ответов, тегами и сложностью
+type BankQuestion struct {
+	TeacherQuestion
+	Tags        []string `json:"tags"`
+	Difficulty  int      `json:"difficulty"`   // от 1 до 5, 0 — не задана
+	UsedIn      int      `json:"used_in"`      // сколько тестов ссылаются на вопрос
+	AttemptUses int      `json:"attempt_uses"` // в скольких попытках был вопрос; при удалении он архивируется
+}
+
+// ДTO для создания и изменения банка
+type BankRequest struct {
+	Token    string `json:"token"`
+	BankID   int    `json:"bank_id,omitempty"`
+	CourseID int    `json:"course_id,omitempty"`
+	Title    string `json:"title" validate:"required,min=1,max=255"`
+	Shared   bool   `json:"shared"`
+}
+
+// ДTO для создания и изменения вопроса банка. При изменении варианты ответа и
+// пары сопоставляются с существующими по порядку, поэтому их id сохраняются.
+type BankQuestionRequest struct {
+	Token      string                `json:"token"`
+	BankID     int                   `json:"bank_id,omitempty"`
+	QuestionID int                   `json:"question_id,omitempty"`
+	Question   CreateQuestionRequest `json:"question"`
+	Tags       []string              `json:"tags"`
+	Difficulty int                   `json:"difficulty" validate:"min=0,max=5"`
+}
+
+// Фильтр вопросов банка
+type BankQuestionFilter struct {
+	Tag        string
+	Difficulty int
+}
@@ -0,0 +1,111 @@
+package models
+
+import "api/internal/relation"
+
+// ДTO для проверки свойств отношения тренажером. Отношение задается парами
+// или матрицей смежности в порядке elements; condition — свойство или вид
+// отношения, пустое значение — все свойства и виды
+type ExplainRelationRequest struct {
+	Elements  []string       `json:"elements"`
+	Pairs     []RelationPair `json:"pairs"`
+	Matrix    [][]int        `json:"matrix,omitempty"`
+	Condition string         `json:"condition,omitempty"`
+}
+
+// Результат проверки: отношение и объяснение по каждому условию
+type ExplainRelationResponse struct {
+	Relation     *relation.Relation     `json:"relation"`
+	Explanations []relation.Explanation `json:"explanations"`
+}
+
+// ДTO для предпросмотра упражнения преподавателем; при seed = 0
+// зерно выбирается случайно и возвращается вместе с упражнением
+type GenerateExerciseRequest struct {
+	Token string `json:"token"`
+	Kind  string `json:"kind"`
+	Size  int    `json:"size"`
+	Seed  int64  `json:"seed"`
+}
+
+// ДTO для разбора текстовой записи отношения. Если elements переданы,
+// все элементы записи должны им принадлежать; иначе множество берется
+// из объявления A = {...} в записи или из самих пар
+type ParseRelationRequest struct {
+	Text     string   `json:"text"`
+	Elements []string `json:"elements,omitempty"`
+}
+
+// Разобранное отношение и вид записи: pairs, matrix, adjacency или predicate
+type ParseRelationResponse struct {
+	Relation *relation.Relation `json:"relation"`
+	Format   string             `json:"format"`
+}
+
+// ДTO для классов эквивалентности в тренажере: отношение задается парами
+// или матрицей; если передано разбиение partition, по нему строится отношение
+type EquivalenceRequest struct {
+	Elements  []string       `json:"elements"`
+	Pairs     []RelationPair `json:"pairs"`
+	Matrix    [][]int        `json:"matrix,omitempty"`
+	Partition [][]string     `json:"partition,omitempty"`
+}
+
+// Отношение и его фактор-множество; если отношение не эквивалентность,
+// вместо классов возвращается объяснение с контрпримером
+type EquivalenceResponse struct {
+	Relation      *relation.Relation    `json:"relation"`
+	IsEquivalence bool                  `json:"is_equivalence"`
+	Classes       [][]string            `json:"classes,omitempty"`
+	Explanation   *relation.Explanation `json:"explanation,omitempty"`
+}
+
+// ДTO для анализа частичного порядка в тренажере; subset — подмножество,
+// для которого нужны верхние и нижние грани (необязательно)
+type PosetRequest struct {
+	Elements []string       `json:"elements"`
+	Pairs    []RelationPair `json:"pairs"`
+	Matrix   [][]int        `json:"matrix,omitempty"`
+	Subset   []string       `json:"subset,omitempty"`
+}
+
+// Результат анализа частичного порядка. Если отношение не частичный порядок,
+// заполнено только объяснение с контрпримером.
+type PosetResponse struct {
+	Relation       *relation.Relation    `json:"relation"`
+	IsPartialOrder bool                  `json:"is_partial_order"`
+	Explanation    *relation.Explanation `json:"explanation,omitempty"`
+	// Отношение покрытия — ребра диаграммы Хассе
+	Covering *relation.Relation `json:"covering,omitempty"`
+	Minimal  []string           `json:"minimal,omitempty"`
+	Maximal  []string           `json:"maximal,omitempty"`
+	Least    *string            `json:"least,omitempty"`
+	Greatest *string            `json:"greatest,omitempty"`
+	// Решетка: у любых двух элементов есть точные верхняя и нижняя грани
+	IsLattice        bool              `json:"is_lattice"`
+	LatticeViolation *LatticeViolation `json:"lattice_violation,omitempty"`
+	Subset           *SubsetBounds     `json:"subset,omitempty"`
+}
+
+// Пара элементов, у которой нет точной грани; missing — supremum или infimum
+type LatticeViolation struct {
+	Pair    relation.Pair `json:"pair"`
+	Missing string        `json:"missing"`
+	Text    string        `json:"text"`
+}
+
+// Верхние и нижние грани подмножества, точные грани — если существуют
+type SubsetBounds struct {
+	Elements []string `json:"elements"`
+	Upper    []string `json:"upper"`
+	Lower    []string `json:"lower"`
+	Supremum *string  `json:"supremum,omitempty"`
+	Infimum  *string  `json:"infimum,omitempty"`
+}
+
+// Параметры изображения отношения в SVG: вид graph, matrix или hasse,
+// элементы и матрица смежности в их порядке
+type RenderRelationRequest struct {
+	View     string
+	Elements []string
+	Matrix   [][]int
+}
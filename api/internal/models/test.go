@@ -1,6 +1,8 @@
 package models
 
 import (
+	"api/internal/exercise"
+	"api/internal/relation"
 	"encoding/json"
 	"time"
 )
@@ -13,18 +15,79 @@ type Test struct {
 	EndDate    time.Time `json:"end_date"`
 	Duration   int       `json:"duration"` // в секундах
 	Attempts   int       `json:"attempts"`
+	// Студенты могут просматривать тест после завершения попыток
+	ResultsReleased bool `json:"results_released"`
+	// Перемешивание вопросов и вариантов ответа для каждой попытки
+	ShuffleQuestions bool `json:"shuffle_questions"`
+	ShuffleOptions   bool `json:"shuffle_options"`
+	// Разделы с пулами вопросов (заполняется для преподавателя)
+	Sections []TestSection `json:"sections,omitempty"`
+	// Номер версии; растет при изменении теста, по которому уже есть попытки
+	Version int `json:"version"`
+	// Постоянный идентификатор для переноса теста пакетом между экземплярами
+	ExternalID string `json:"external_id"`
+}
+
+// Раздел теста: из его вопросов в каждую попытку берется DrawCount случайных
+type TestSection struct {
+	ID        int    `json:"id"`
+	TestID    int    `json:"test_id"`
+	Title     string `json:"title"`
+	DrawCount int    `json:"draw_count"`
+	Position  int    `json:"position"`
 }
 
 type Question struct {
 	ID           int            `json:"id"`
 	TestID       int            `json:"test_id"`
 	QuestionText string         `json:"question_text"`
-	QuestionType string         `json:"question_type"` // single_choice, multiple_choice, text_answer, matching
+	QuestionType string         `json:"question_type"` // single_choice, multiple_choice, text_answer, matching, relation
 	Points       int            `json:"points"`
 	Position     int            `json:"position"`
+	ScoringMode  string         `json:"scoring_mode,omitempty"` // all_or_nothing, partial
+	SectionID    *int           `json:"section_id,omitempty"`   // nil — вопрос входит в каждую попытку
+	BankID       *int           `json:"bank_id,omitempty"`      // вопрос взят из банка курса
 	Options      []AnswerOption `json:"options,omitempty"`
 }
 
+// Вопрос в том виде, в котором его видит студент: без признаков правильности
+type StudentQuestion struct {
+	ID           int             `json:"id"`
+	TestID       int             `json:"test_id"`
+	QuestionText string          `json:"question_text"`
+	QuestionType string          `json:"question_type"`
+	Points       int             `json:"points"`
+	Position     int             `json:"position"`
+	Options      []StudentOption `json:"options"`
+	// Для вопросов на сопоставление: левая колонка по порядку, правая — перемешана
+	MatchingLeft  []MatchingItem `json:"matching_left,omitempty"`
+	MatchingRight []MatchingItem `json:"matching_right,omitempty"`
+	// Для вопросов об отношении: элементы множества в порядке строк матрицы
+	Elements []string `json:"elements,omitempty"`
+	// Для вопросов об отношении: форма ответа — pairs, properties или classes
+	AnswerForm string `json:"answer_form,omitempty"`
+	// Для вопросов на разбиение: отношение эквивалентности из условия
+	Relation *relation.Relation `json:"relation,omitempty"`
+	// Для вопросов со сгенерированным упражнением: вариант этой попытки
+	Exercise *exercise.Task `json:"exercise,omitempty"`
+}
+
+// Вариант ответа без признака правильности
+type StudentOption struct {
+	ID         int    `json:"id"`
+	QuestionID int    `json:"question_id"`
+	OptionText string `json:"option_text"`
+	Position   int    `json:"position"`
+}
+
+// Вопрос с полным ключом ответов для преподавателя курса
+type TeacherQuestion struct {
+	Question
+	Pairs       []MatchingPair   `json:"pairs,omitempty"`
+	AnswerRules []TextAnswerRule `json:"answer_rules,omitempty"`
+	Relation    *RelationSpec    `json:"relation,omitempty"`
+}
+
 type AnswerOption struct {
 	ID         int    `json:"id"`
 	QuestionID int    `json:"question_id"`
@@ -33,14 +96,82 @@ type AnswerOption struct {
 	Position   int    `json:"position"`
 }
 
+// Пара для вопроса на сопоставление
+type MatchingPair struct {
+	ID         int    `json:"id"`
+	QuestionID int    `json:"question_id"`
+	LeftText   string `json:"left_text"`
+	RightText  string `json:"right_text"`
+	RightKey   int    `json:"right_key"`
+	Position   int    `json:"position"`
+}
+
+// Элемент колонки сопоставления, который видит студент
+type MatchingItem struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// Ответ на вопрос на сопоставление:
+// {"matches": [{"left_id": 1, "right_id": 734}, ...]}
+type MatchingAnswer struct {
+	Matches []struct {
+		LeftID  int `json:"left_id"`
+		RightID int `json:"right_id"`
+	} `json:"matches"`
+}
+
+// Задание вопроса об отношении на множестве Elements. В режиме pairs ответ
+// сравнивается с отношением Pairs, в режиме properties — проверяется, что
+// выполняются свойства или виды отношения из Required и не выполняются из Forbidden,
+// в режиме partition студент разбивает множество на классы эквивалентности Pairs.
+// В режиме generated каждая попытка получает свое упражнение вида Exercise
+// на множестве из Size элементов, а ключ ответа вычисляется по нему.
+type RelationSpec struct {
+	Elements  []string       `json:"elements" validate:"required,min=1"`
+	Mode      string         `json:"mode" validate:"required,oneof=pairs properties partition generated"`
+	Pairs     []RelationPair `json:"pairs,omitempty" validate:"dive"`
+	Required  []string       `json:"required,omitempty"`
+	Forbidden []string       `json:"forbidden,omitempty"`
+	Exercise  string         `json:"exercise,omitempty"`
+	Size      int            `json:"size,omitempty"`
+}
+
+// Упорядоченная пара отношения: From R To
+type RelationPair struct {
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+}
+
+// Ответ на вопрос об отношении: пары {"pairs": [{"from": "a", "to": "b"}, ...]}
+// или матрица смежности {"matrix": [[1, 0], [0, 1]]} в порядке elements вопроса.
+// Вопросы на разбиение и сгенерированные упражнения на свойства отвечаются классами
+// {"classes": [["a", "b"], ["c"]]} или списком свойств {"properties": ["reflexive", ...]}.
+// Отношение можно задать и текстом {"text": "{(a, b), (b, c)}"} в любой записи,
+// которую понимает пакет notation: пары, матрица, список смежности или условие.
+type RelationAnswer struct {
+	Pairs      []RelationPair `json:"pairs"`
+	Matrix     [][]int        `json:"matrix,omitempty"`
+	Text       string         `json:"text,omitempty"`
+	Properties []string       `json:"properties,omitempty"`
+	Classes    [][]string     `json:"classes,omitempty"`
+}
+
 type TestAttempt struct {
 	ID         int        `json:"id"`
 	UserID     int        `json:"user_id"`
 	TestID     int        `json:"test_id"`
 	StartedAt  time.Time  `json:"started_at"`
+	DeadlineAt *time.Time `json:"deadline_at,omitempty"`
 	FinishedAt *time.Time `json:"finished_at,omitempty"`
 	Score      *int       `json:"score,omitempty"`
-	Status     string     `json:"status"` // in_progress, completed, expired
+	MaxScore   *int       `json:"max_score,omitempty"`
+	Percentage *float64   `json:"percentage,omitempty"`
+	Status     string     `json:"status"` // in_progress, pending_review, completed, expired
+	// Зерно перестановки вопросов и вариантов; клиенту не отдается
+	ShuffleSeed int64 `json:"-"`
+	// Версия теста, на которой начата попытка
+	TestVersion int `json:"test_version"`
 }
 
 type UserAnswer struct {
@@ -49,6 +180,78 @@ type UserAnswer struct {
 	QuestionID   int             `json:"question_id"`
 	AnswerData   json.RawMessage `json:"answer_data"`
 	PointsEarned int             `json:"points_earned"`
+	NeedsReview  bool            `json:"needs_review"`
+	Comment      *string         `json:"reviewer_comment,omitempty"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	// Разбор ответа на вопрос об отношении
+	Feedback *RelationFeedback `json:"feedback,omitempty"`
+}
+
+// Разбор ответа на вопрос об отношении: пропущенные и лишние пары
+// (режим pairs) или нарушенные условия с контрпримерами (режим properties)
+type RelationFeedback struct {
+	Missing    []RelationPair         `json:"missing,omitempty"`
+	Extra      []RelationPair         `json:"extra,omitempty"`
+	Violations []relation.Explanation `json:"violations,omitempty"`
+}
+
+// Разбор завершенной попытки: ответы с баллами, комментариями и разбором
+type AttemptReview struct {
+	Attempt *TestAttempt `json:"attempt"`
+	Answers []UserAnswer `json:"answers"`
+}
+
+// Сохраненный ответ, который студент видит при продолжении попытки
+// (без баллов, чтобы не раскрывать правильность до завершения)
+type SavedAnswer struct {
+	QuestionID int             `json:"question_id"`
+	AnswerData json.RawMessage `json:"answer_data"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// Состояние незавершенной попытки для продолжения после перезагрузки
+type AttemptProgress struct {
+	Attempt *TestAttempt  `json:"attempt"`
+	Answers []SavedAnswer `json:"answers"`
+}
+
+// Ответ, ожидающий ручной проверки преподавателем
+type PendingAnswer struct {
+	AnswerID     int             `json:"answer_id"`
+	AttemptID    int             `json:"attempt_id"`
+	QuestionID   int             `json:"question_id"`
+	Username     string          `json:"username"`
+	TestID       int             `json:"test_id"`
+	TestTitle    string          `json:"test_title"`
+	CourseID     int             `json:"course_id"`
+	CourseName   string          `json:"course_name"`
+	QuestionText string          `json:"question_text"`
+	MaxPoints    int             `json:"max_points"`
+	AnswerData   json.RawMessage `json:"answer_data"`
+	FinishedAt   *time.Time      `json:"finished_at,omitempty"`
+}
+
+// ДTO для выставления баллов за ответ
+type GradeAnswerRequest struct {
+	Token    string `json:"token"`
+	AnswerID int    `json:"answer_id"`
+	Points   int    `json:"points" validate:"min=0"`
+	Comment  string `json:"comment"`
+}
+
+// Правило проверки текстового ответа
+type TextAnswerRule struct {
+	ID         int     `json:"id"`
+	QuestionID int     `json:"question_id"`
+	RuleType   string  `json:"rule_type"` // exact, normalized, regex, numeric, pair_set
+	Pattern    string  `json:"pattern"`
+	Tolerance  float64 `json:"tolerance,omitempty"`
+	Position   int     `json:"position"`
+}
+
+// Ответ на текстовый вопрос: {"answer_text": "..."}
+type TextAnswer struct {
+	AnswerText string `json:"answer_text"`
 }
 
 // ДTO для создания теста
@@ -60,15 +263,44 @@ type CreateTestRequest struct {
 	Attempts  int                     `json:"attempts" validate:"min=0"`
 	EndDate   time.Time               `json:"end_date"`
 	Questions []CreateQuestionRequest `json:"questions"`
+	// Перемешивать вопросы и варианты ответа в каждой попытке
+	ShuffleQuestions bool `json:"shuffle_questions"`
+	ShuffleOptions   bool `json:"shuffle_options"`
+	// Разделы с пулами вопросов; вопрос ссылается на раздел по номеру
+	Sections []CreateSectionRequest `json:"sections,omitempty" validate:"dive"`
+	// Заполняются только при импорте пакета теста: внешний идентификатор
+	// и срок сдачи в секундах от даты создания теста
+	ExternalID    string `json:"-"`
+	EndDateOffset *int64 `json:"-"`
+}
+
+// ДTO для изменения теста: содержимое заменяется целиком
+type UpdateTestRequest struct {
+	TestID int `json:"test_id"`
+	CreateTestRequest
+}
+
+// ДTO для создания раздела теста
+type CreateSectionRequest struct {
+	Title     string `json:"title"`
+	DrawCount int    `json:"draw_count" validate:"min=1"`
+	Position  int    `json:"position" validate:"min=0"`
 }
 
 // ДTO для создания вопроса
 type CreateQuestionRequest struct {
-	QuestionText string                      `json:"question_text" validate:"required,min=3"`
-	QuestionType string                      `json:"question_type" validate:"required,oneof=single_choice multiple_choice text matching"`
-	Points       int                         `json:"points" validate:"min=0"`
-	Position     int                         `json:"position" validate:"min=0"`
-	Options      []CreateAnswerOptionRequest `json:"options,omitempty" validate:"dive"`
+	QuestionText string `json:"question_text" validate:"required,min=3"`
+	QuestionType string `json:"question_type" validate:"required,oneof=single_choice multiple_choice text matching relation"`
+	Points       int    `json:"points" validate:"min=0"`
+	Position     int    `json:"position" validate:"min=0"`
+	ScoringMode  string `json:"scoring_mode,omitempty" validate:"omitempty,oneof=all_or_nothing partial"`
+	Section      int    `json:"section,omitempty" validate:"min=0"` // номер раздела в sections с 1; 0 — без раздела
+	// Ссылка на вопрос банка курса вместо копии; остальные поля, кроме position и section, игнорируются
+	BankQuestionID int                           `json:"bank_question_id,omitempty"`
+	Options        []CreateAnswerOptionRequest   `json:"options,omitempty" validate:"dive"`
+	Pairs          []CreateMatchingPairRequest   `json:"pairs,omitempty" validate:"dive"`
+	AnswerRules    []CreateTextAnswerRuleRequest `json:"answer_rules,omitempty" validate:"dive"`
+	Relation       *RelationSpec                 `json:"relation,omitempty"`
 }
 
 // ДTO для создания варианта ответа
@@ -77,3 +309,63 @@ type CreateAnswerOptionRequest struct {
 	IsCorrect  bool   `json:"is_correct"`
 	Position   int    `json:"position" validate:"min=0"`
 }
+
+// ДTO для создания пары сопоставления
+type CreateMatchingPairRequest struct {
+	LeftText  string `json:"left_text" validate:"required,min=1"`
+	RightText string `json:"right_text" validate:"required,min=1"`
+	Position  int    `json:"position" validate:"min=0"`
+}
+
+// ДTO для создания правила проверки текстового ответа
+type CreateTextAnswerRuleRequest struct {
+	RuleType  string  `json:"rule_type" validate:"required,oneof=exact normalized regex numeric pair_set"`
+	Pattern   string  `json:"pattern" validate:"required,min=1"`
+	Tolerance float64 `json:"tolerance" validate:"min=0"`
+	Position  int     `json:"position" validate:"min=0"`
+}
+
+// ДTO для импорта теста из Moodle XML или GIFT
+type ImportTestRequest struct {
+	Token    string    `json:"token"`
+	CourseID int       `json:"course_id"`
+	Format   string    `json:"format"` // moodle, gift
+	Content  string    `json:"content"`
+	Title    string    `json:"title"`
+	Duration int       `json:"duration"` // в секундах
+	Attempts int       `json:"attempts"`
+	EndDate  time.Time `json:"end_date"`
+	DryRun   bool      `json:"dry_run"` // только проверить файл, ничего не сохраняя
+}
+
+// Замечание к вопросу импортируемого файла
+type ImportIssue struct {
+	Question int    `json:"question"` // номер вопроса в файле с 1
+	Title    string `json:"title,omitempty"`
+	Severity string `json:"severity"` // warning — импортирован с упрощением, skipped — пропущен
+	Message  string `json:"message"`
+}
+
+// Итог импорта (или проверки при dry_run)
+type ImportReport struct {
+	DryRun    bool                    `json:"dry_run"`
+	Imported  int                     `json:"imported"`
+	Skipped   int                     `json:"skipped"`
+	Issues    []ImportIssue           `json:"issues"`
+	Questions []CreateQuestionRequest `json:"questions"`
+	Test      *Test                   `json:"test,omitempty"`
+}
+
+// ДTO для импорта пакета теста в собственном формате
+type ImportPackageRequest struct {
+	Token    string          `json:"token"`
+	CourseID int             `json:"course_id"`
+	Package  json.RawMessage `json:"package"`
+}
+
+// Итог импорта пакета: created — тест создан, updated — тест с тем же
+// внешним идентификатором изменен, unchanged — тест уже совпадает с пакетом
+type ImportPackageResult struct {
+	Status string `json:"status"`
+	Test   *Test  `json:"test"`
+}
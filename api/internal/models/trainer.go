@@ -0,0 +1,123 @@
+package models
+
+import (
+	"api/internal/relation"
+	"time"
+)
+
+// Отношение, сохраненное студентом в тренажере
+type TrainerRelation struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	Elements  []string       `json:"elements"`
+	Pairs     []RelationPair `json:"pairs"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ДTO для сохранения, загрузки и удаления отношений тренажера. При сохранении
+// отношение задается парами или матрицей в порядке elements; загрузка
+// и удаление — по id
+type TrainerRelationRequest struct {
+	Token    string         `json:"token"`
+	ID       int            `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Elements []string       `json:"elements"`
+	Pairs    []RelationPair `json:"pairs"`
+	Matrix   [][]int        `json:"matrix,omitempty"`
+}
+
+// ДTO для проверки свойств в тренажере вошедшим пользователем:
+// то же, что ExplainRelationRequest, но проверка записывается в журнал
+type TrainerCheckRequest struct {
+	Token string `json:"token"`
+	ExplainRelationRequest
+}
+
+// Запись журнала проверок тренажера; Holds пуст, если проверялись все свойства
+type TrainerCheck struct {
+	UserID      int
+	RelationKey string
+	Condition   string
+	Holds       *bool
+}
+
+// ДTO для отчета преподавателя о практике группы в тренажере. Без group_id
+// берется первая группа преподавателя; from и to ограничивают период
+type TrainerActivityRequest struct {
+	Token   string     `json:"token"`
+	GroupID int        `json:"group_id,omitempty"`
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+}
+
+// Практика группы в тренажере и ее группы, доступные преподавателю
+type TrainerActivity struct {
+	Groups   []Group           `json:"groups"`
+	GroupID  int               `json:"group_id"`
+	Students []StudentPractice `json:"students"`
+}
+
+// Практика студента в тренажере рядом с результатами тестов по курсам
+// преподавателя, чтобы их можно было сопоставить
+type StudentPractice struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+	// Время в тренажере: сумма промежутков между соседними проверками,
+	// не длиннее перерыва, после которого сеанс считается оконченным
+	TimeSpentSeconds  int            `json:"time_spent_seconds"`
+	RelationsExplored int            `json:"relations_explored"`
+	Checks            int            `json:"checks"`
+	ChecksByCondition map[string]int `json:"checks_by_condition"`
+	SavedRelations    int            `json:"saved_relations"`
+	LastActivity      *time.Time     `json:"last_activity,omitempty"`
+	SolvedTasks       int            `json:"solved_tasks"`
+	FinishedAttempts  int            `json:"finished_attempts"`
+	AveragePercentage *float64       `json:"average_percentage,omitempty"`
+}
+
+// Задание тренажера: построить на множестве elements отношение, у которого
+// есть свойства (или виды) required и нет свойств forbidden. Дополнительно
+// задание может требовать число классов эквивалентности, порядок, не
+// являющийся решеткой, или наименьшее число пар.
+type TrainerTask struct {
+	ID         string   `json:"id"`
+	Level      int      `json:"level"` // сложность от 1 до 4
+	Elements   []string `json:"elements"`
+	Prompt     string   `json:"prompt"`
+	Required   []string `json:"required"`
+	Forbidden  []string `json:"forbidden"`
+	Classes    int      `json:"classes,omitempty"`
+	NotLattice bool     `json:"not_lattice,omitempty"`
+	MinPairs   int      `json:"min_pairs,omitempty"`
+	// Прогресс студента: число проверок и время первого решения
+	Attempts int        `json:"attempts"`
+	SolvedAt *time.Time `json:"solved_at,omitempty"`
+}
+
+// Прогресс студента по заданию тренажера
+type TrainerTaskProgress struct {
+	Attempts int
+	SolvedAt *time.Time
+}
+
+// ДTO для списка заданий (level = 0 — все уровни) и проверки решения:
+// отношение задается парами или матрицей в порядке элементов задания
+type TrainerTaskRequest struct {
+	Token  string         `json:"token"`
+	Level  int            `json:"level,omitempty"`
+	TaskID string         `json:"task_id,omitempty"`
+	Pairs  []RelationPair `json:"pairs"`
+	Matrix [][]int        `json:"matrix,omitempty"`
+}
+
+// Результат проверки решения: при неудаче — нарушенные условия
+// с контрпримерами, первое из них тренажер показывает студенту
+type TrainerTaskVerdict struct {
+	TaskID     string                 `json:"task_id"`
+	Solved     bool                   `json:"solved"`
+	Relation   *relation.Relation     `json:"relation"`
+	Violations []relation.Explanation `json:"violations"`
+	Attempts   int                    `json:"attempts"`
+	SolvedAt   *time.Time             `json:"solved_at,omitempty"`
+}
@@ -0,0 +1,665 @@
+// Пакет notation разбирает текстовую запись отношения в тех видах, в которых
+// ее набирают преподаватели и студенты: множество пар {(a, b), (b, c)},
+// матрица из нулей и единиц, список смежности (a: b, c) и предикат на
+// отрезке целых чисел ({(x, y) ∈ A×A | x divides y} при A = {1..12}).
+// Ошибки содержат строку и столбец, в которых разбор остановился.
+package notation
+
+import (
+	"api/internal/relation"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Format — вид записи отношения
+type Format string
+
+const (
+	FormatPairs     Format = "pairs"
+	FormatMatrix    Format = "matrix"
+	FormatAdjacency Format = "adjacency"
+	FormatPredicate Format = "predicate"
+)
+
+// Наибольший размер отрезка {m..n} в объявлении множества
+const MaxRange = 100
+
+// SyntaxError — ошибка разбора с позицией (строка и столбец считаются с единицы)
+type SyntaxError struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Msg    string `json:"message"`
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Result — разобранное отношение и вид записи, в котором оно было задано
+type Result struct {
+	Relation *relation.Relation `json:"relation"`
+	Format   Format             `json:"format"`
+}
+
+// Parse разбирает запись отношения. Если множество известно заранее (вопрос
+// теста), оно передается в elements, и все элементы записи должны ему
+// принадлежать. Иначе множество берется из объявления вида A = {a, b, c}
+// или A = {1..12}, а без объявления — из самой записи: элементы пар и списка
+// смежности по порядку появления, номера 1..n для матрицы.
+func Parse(input string, elements []string) (*Result, error) {
+	p := &parser{src: []rune(input)}
+	p.tokens = p.tokenize()
+
+	body, domain, err := p.splitDomain()
+	if err != nil {
+		return nil, err
+	}
+	if elements != nil {
+		if domain != nil && !sameElements(domain.elements, elements) {
+			return nil, p.errorf(domain.off, "set must be {%s}", strings.Join(elements, ", "))
+		}
+		domain = &domainDecl{elements: elements, integers: integerValues(elements), off: -1}
+	}
+
+	body = trimNewlines(body)
+	if len(body) == 0 {
+		return nil, p.errorf(len(p.src), "expected a relation")
+	}
+
+	var format Format
+	var pairs []located
+	switch {
+	case body[0].is("∅") || body[0].is("{") && len(body) > 1 && body[1].is("}"):
+		format, err = FormatPairs, p.expectEnd(body, 1+boolInt(body[0].is("{")))
+	case body[0].is("{") && isBuilder(body):
+		format = FormatPredicate
+		pairs, err = p.parseBuilder(body, domain)
+	case body[0].is("{") || body[0].is("(") && startsWithPair(body):
+		format = FormatPairs
+		pairs, err = p.parsePairs(body)
+	case isMatrix(body):
+		format = FormatMatrix
+		var r *relation.Relation
+		r, err = p.parseMatrix(body, domain)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Relation: r, Format: format}, nil
+	case isAdjacency(body):
+		format = FormatAdjacency
+		pairs, err = p.parseAdjacency(body)
+	default:
+		format = FormatPredicate
+		pairs, err = p.parsePredicate(body, [2]string{"x", "y"}, domain)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := p.build(pairs, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Relation: r, Format: format}, nil
+}
+
+// located — пара вместе с позицией в записи, чтобы указать на неизвестный элемент
+type located struct {
+	pair     relation.Pair
+	from, to int
+}
+
+// build создает отношение на объявленном множестве или на элементах пар
+func (p *parser) build(pairs []located, domain *domainDecl) (*relation.Relation, error) {
+	var names []string
+	if domain != nil {
+		names = domain.elements
+	} else {
+		seen := map[string]bool{}
+		for _, lp := range pairs {
+			for _, e := range []string{lp.pair.From, lp.pair.To} {
+				if !seen[e] {
+					seen[e] = true
+					names = append(names, e)
+				}
+			}
+		}
+	}
+
+	set, err := relation.NewSet(names...)
+	if err != nil {
+		return nil, err
+	}
+	r := relation.New(set)
+	for _, lp := range pairs {
+		if !set.Contains(lp.pair.From) {
+			return nil, p.errorf(lp.from, "element %q is not in the set", lp.pair.From)
+		}
+		if !set.Contains(lp.pair.To) {
+			return nil, p.errorf(lp.to, "element %q is not in the set", lp.pair.To)
+		}
+		_ = r.Add(lp.pair.From, lp.pair.To)
+	}
+	return r, nil
+}
+
+// Лексемы
+
+type tokenKind int
+
+const (
+	tWord tokenKind = iota // имя или число
+	tSym                   // знак
+	tNewline
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	off  int // смещение в символах от начала записи
+}
+
+func (t token) is(s string) bool {
+	return t.kind != tNewline && t.text == s
+}
+
+func (t token) isWord(words ...string) bool {
+	if t.kind != tWord {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) number() (int64, bool) {
+	if t.kind != tWord {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(t.text, 10, 64)
+	return v, err == nil
+}
+
+// Знаки из нескольких символов
+var multiSymbols = []string{"..", "->", "<=", ">=", "!=", "==", "&&", "||"}
+
+type parser struct {
+	src    []rune
+	tokens []token
+}
+
+func (p *parser) tokenize() []token {
+	var tokens []token
+	for i := 0; i < len(p.src); {
+		c := p.src[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, token{tNewline, "\n", i})
+			i++
+		case unicode.IsSpace(c) || c == '\uFEFF':
+			i++
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_':
+			start := i
+			for i < len(p.src) && (unicode.IsLetter(p.src[i]) || unicode.IsDigit(p.src[i]) || p.src[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tWord, string(p.src[start:i]), start})
+		default:
+			text := string(c)
+			for _, s := range multiSymbols {
+				if strings.HasPrefix(string(p.src[i:min(i+2, len(p.src))]), s) {
+					text = s
+					break
+				}
+			}
+			tokens = append(tokens, token{tSym, text, i})
+			i += len([]rune(text))
+		}
+	}
+	return tokens
+}
+
+// errorf создает ошибку с позицией символа off
+func (p *parser) errorf(off int, format string, args ...any) *SyntaxError {
+	line, col := 1, 1
+	for i := 0; i < off && i < len(p.src); i++ {
+		if p.src[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// errorAt сообщает об ошибке на лексеме tokens[i] или в конце записи
+func (p *parser) errorAt(tokens []token, i int, format string, args ...any) *SyntaxError {
+	if i < len(tokens) {
+		return p.errorf(tokens[i].off, format, args...)
+	}
+	return p.errorf(len(p.src), format, args...)
+}
+
+func (p *parser) expectEnd(tokens []token, i int) error {
+	for ; i < len(tokens); i++ {
+		if tokens[i].kind != tNewline {
+			return p.errorf(tokens[i].off, "unexpected %q after the relation", tokens[i].text)
+		}
+	}
+	return nil
+}
+
+// Объявление множества
+
+type domainDecl struct {
+	elements []string
+	integers []int64 // значения элементов, если все они целые
+	off      int
+}
+
+// splitDomain находит объявление множества вида A = {...}, вырезает его из
+// записи вместе со связкой перед ним (on, на, где, запятая) и разбирает
+func (p *parser) splitDomain() ([]token, *domainDecl, error) {
+	tokens := p.tokens
+	for i := 0; i+2 < len(tokens); i++ {
+		name := []rune(tokens[i].text)
+		if tokens[i].kind != tWord || len(name) != 1 || !unicode.IsUpper(name[0]) ||
+			!tokens[i+1].is("=") || !tokens[i+2].is("{") {
+			continue
+		}
+
+		end := i + 3
+		for end < len(tokens) && !tokens[end].is("}") {
+			end++
+		}
+		if end == len(tokens) {
+			return nil, nil, p.errorAt(tokens, end, "expected '}' to close the set")
+		}
+		domain, err := p.parseDomain(tokens[i+3:end], tokens[i+2].off)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		start := i
+		for start > 0 && (tokens[start-1].isWord("on", "на", "where", "где", "при") ||
+			tokens[start-1].is(",") || tokens[start-1].is(";")) {
+			start--
+		}
+		body := append(append([]token{}, tokens[:start]...), tokens[end+1:]...)
+		return body, domain, nil
+	}
+	return tokens, nil, nil
+}
+
+// parseDomain разбирает содержимое фигурных скобок: отрезок m..n или список элементов
+func (p *parser) parseDomain(tokens []token, off int) (*domainDecl, error) {
+	tokens = skipNewlines(tokens)
+	domain := &domainDecl{off: off}
+
+	if i := indexOf(tokens, ".."); i >= 0 {
+		from, err := p.signedNumber(tokens[:i], off)
+		if err != nil {
+			return nil, err
+		}
+		to, err := p.signedNumber(tokens[i+1:], tokens[i].off)
+		if err != nil {
+			return nil, err
+		}
+		if to < from {
+			return nil, p.errorf(tokens[i].off, "range %d..%d is empty", from, to)
+		}
+		// Разность сравнивается как uint64: to-from+1 переполняет int64 на больших границах
+		if uint64(to)-uint64(from) >= MaxRange {
+			return nil, p.errorf(tokens[i].off, "range %d..%d has more than %d elements", from, to, MaxRange)
+		}
+		for v := from; v <= to; v++ {
+			domain.elements = append(domain.elements, strconv.FormatInt(v, 10))
+			domain.integers = append(domain.integers, v)
+		}
+		return domain, nil
+	}
+
+	integers := true
+	for i := 0; i < len(tokens); {
+		e, next, err := p.element(tokens, i)
+		if err != nil {
+			return nil, err
+		}
+		for _, seen := range domain.elements {
+			if seen == e {
+				return nil, p.errorf(tokens[i].off, "element %q is listed twice", e)
+			}
+		}
+		domain.elements = append(domain.elements, e)
+		if v, err := strconv.ParseInt(e, 10, 64); err == nil {
+			domain.integers = append(domain.integers, v)
+		} else {
+			integers = false
+		}
+		i = next
+		if i < len(tokens) {
+			if !tokens[i].is(",") {
+				return nil, p.errorf(tokens[i].off, "expected ',' between elements")
+			}
+			i++
+		}
+	}
+	if !integers {
+		domain.integers = nil
+	}
+	return domain, nil
+}
+
+// integerValues возвращает значения элементов, если все они целые, иначе nil
+func integerValues(elements []string) []int64 {
+	values := make([]int64, len(elements))
+	for i, e := range elements {
+		v, err := strconv.ParseInt(e, 10, 64)
+		if err != nil {
+			return nil
+		}
+		values[i] = v
+	}
+	return values
+}
+
+// signedNumber разбирает целое со знаком, занимающее все лексемы
+func (p *parser) signedNumber(tokens []token, off int) (int64, error) {
+	tokens = skipNewlines(tokens)
+	sign := int64(1)
+	if len(tokens) > 0 && tokens[0].is("-") {
+		sign = -1
+		tokens = tokens[1:]
+	}
+	if len(tokens) != 1 {
+		if len(tokens) > 1 {
+			off = tokens[1].off
+		}
+		return 0, p.errorf(off, "expected an integer in the range")
+	}
+	v, ok := tokens[0].number()
+	if !ok {
+		return 0, p.errorf(tokens[0].off, "expected an integer, got %q", tokens[0].text)
+	}
+	return sign * v, nil
+}
+
+// element разбирает имя элемента (слово или целое со знаком минус)
+func (p *parser) element(tokens []token, i int) (string, int, error) {
+	if i < len(tokens) && tokens[i].is("-") && i+1 < len(tokens) {
+		if _, ok := tokens[i+1].number(); ok && tokens[i+1].off == tokens[i].off+1 {
+			return "-" + tokens[i+1].text, i + 2, nil
+		}
+	}
+	if i >= len(tokens) || tokens[i].kind != tWord {
+		return "", i, p.errorAt(tokens, i, "expected an element")
+	}
+	return tokens[i].text, i + 1, nil
+}
+
+// Множество пар
+
+// parsePairs разбирает {(a, b), (b, c)} или те же пары без фигурных скобок
+func (p *parser) parsePairs(tokens []token) ([]located, error) {
+	tokens = skipNewlines(tokens)
+	braced := tokens[0].is("{")
+	i := 0
+	if braced {
+		i = 1
+	}
+
+	var pairs []located
+	for {
+		if braced && i < len(tokens) && tokens[i].is("}") {
+			return pairs, p.expectEnd(tokens, i+1)
+		}
+		if !braced && i == len(tokens) {
+			return pairs, nil
+		}
+		if i >= len(tokens) || !tokens[i].is("(") {
+			if braced && i >= len(tokens) {
+				return nil, p.errorAt(tokens, i, "expected '}' to close the set of pairs")
+			}
+			return nil, p.errorAt(tokens, i, "expected '(' to start a pair")
+		}
+
+		lp, next, err := p.pair(tokens, i)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, lp)
+		i = next
+		if i < len(tokens) && (tokens[i].is(",") || tokens[i].is(";")) {
+			i++
+		}
+	}
+}
+
+// startsWithPair отличает список пар без скобок от условия в скобках: (x + y) % 2 = 0
+func startsWithPair(tokens []token) bool {
+	tokens = skipNewlines(tokens)
+	i := 1
+	if i < len(tokens) && tokens[i].is("-") {
+		i++
+	}
+	return i+1 < len(tokens) && tokens[i].kind == tWord && tokens[i+1].is(",")
+}
+
+// pair разбирает (a, b), начиная с открывающей скобки tokens[i]
+func (p *parser) pair(tokens []token, i int) (located, int, error) {
+	var lp located
+	i++ // (
+	from, i, err := p.element(tokens, i)
+	if err != nil {
+		return lp, i, err
+	}
+	lp.from = tokens[i-1].off
+	if i >= len(tokens) || !tokens[i].is(",") {
+		return lp, i, p.errorAt(tokens, i, "expected ',' between pair elements")
+	}
+	i++
+	to, i, err := p.element(tokens, i)
+	if err != nil {
+		return lp, i, err
+	}
+	lp.to = tokens[i-1].off
+	if i >= len(tokens) || !tokens[i].is(")") {
+		return lp, i, p.errorAt(tokens, i, "expected ')' to close the pair")
+	}
+	lp.pair = relation.Pair{From: from, To: to}
+	return lp, i + 1, nil
+}
+
+// Матрица
+
+// isMatrix: каждая непустая строка состоит только из нулей и единиц,
+// и хотя бы одна такая строка есть
+// (через пробелы или запятые, можно в квадратных скобках)
+func isMatrix(tokens []token) bool {
+	values := false
+	for _, t := range tokens {
+		switch {
+		case t.kind == tNewline, t.is(","), t.is(";"), t.is("["), t.is("]"):
+		case t.kind == tWord && strings.Trim(t.text, "01") == "":
+			values = true
+		default:
+			return false
+		}
+	}
+	return values
+}
+
+func (p *parser) parseMatrix(tokens []token, domain *domainDecl) (*relation.Relation, error) {
+	var rows [][]bool
+	var starts []int
+	for _, line := range splitLines(tokens) {
+		var row []bool
+		for _, t := range line {
+			if t.kind == tWord {
+				for _, c := range t.text {
+					row = append(row, c == '1')
+				}
+			}
+		}
+		if len(row) == 0 {
+			continue
+		}
+		rows = append(rows, row)
+		starts = append(starts, line[0].off)
+	}
+
+	n := len(rows)
+	if n == 0 {
+		return nil, p.errorf(tokens[0].off, "expected matrix rows of 0 and 1")
+	}
+	if domain != nil && len(domain.elements) != n {
+		return nil, p.errorf(starts[0], "matrix must have %d rows, got %d", len(domain.elements), n)
+	}
+	for i, row := range rows {
+		if len(row) != n {
+			return nil, p.errorf(starts[i], "row %d has %d values, expected %d", i+1, len(row), n)
+		}
+	}
+
+	names := make([]string, n)
+	if domain != nil {
+		names = domain.elements
+	} else {
+		for i := range names {
+			names[i] = strconv.Itoa(i + 1)
+		}
+	}
+	set, err := relation.NewSet(names...)
+	if err != nil {
+		return nil, err
+	}
+	return relation.FromMatrix(set, rows)
+}
+
+// Список смежности
+
+// isAdjacency: хотя бы одна строка имеет вид «элемент: ...» или «элемент -> ...»
+func isAdjacency(tokens []token) bool {
+	for _, line := range splitLines(tokens) {
+		if len(line) >= 2 && line[0].kind == tWord && isArrow(line[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isArrow(t token) bool {
+	return t.is(":") || t.is("->") || t.is("→")
+}
+
+// parseAdjacency разбирает строки «a: b, c»; пустая правая часть — нет пар
+func (p *parser) parseAdjacency(tokens []token) ([]located, error) {
+	var pairs []located
+	for _, line := range splitLines(tokens) {
+		if len(line) == 0 {
+			continue
+		}
+		from, i, err := p.element(line, 0)
+		if err != nil {
+			return nil, err
+		}
+		if i >= len(line) || !isArrow(line[i]) {
+			return nil, p.errorAt(line, i, "expected ':' or '->' after %q", from)
+		}
+		i++
+		if i < len(line) && line[i].is("{") {
+			i++
+			if last := line[len(line)-1]; !last.is("}") {
+				return nil, p.errorf(last.off, "expected '}' at the end of the line")
+			}
+			line = line[:len(line)-1]
+		}
+		if i < len(line) && line[i].is("∅") {
+			i++
+		}
+		for i < len(line) {
+			to, next, err := p.element(line, i)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, located{pair: relation.Pair{From: from, To: to}, from: line[0].off, to: line[i].off})
+			i = next
+			if i < len(line) && line[i].is(",") {
+				i++
+			}
+		}
+	}
+	return pairs, nil
+}
+
+// Вспомогательные функции
+
+func splitLines(tokens []token) [][]token {
+	lines := [][]token{{}}
+	for _, t := range tokens {
+		if t.kind == tNewline {
+			lines = append(lines, []token{})
+			continue
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], t)
+	}
+	return lines
+}
+
+func skipNewlines(tokens []token) []token {
+	result := make([]token, 0, len(tokens))
+	for _, t := range tokens {
+		if t.kind != tNewline {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func trimNewlines(tokens []token) []token {
+	for len(tokens) > 0 && tokens[0].kind == tNewline {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].kind == tNewline {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+func indexOf(tokens []token, s string) int {
+	for i, t := range tokens {
+		if t.is(s) {
+			return i
+		}
+	}
+	return -1
+}
+
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, e := range a {
+		seen[e] = true
+	}
+	for _, e := range b {
+		if !seen[e] {
+			return false
+		}
+	}
+	return true
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package notation
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		elements []string
+		format   Format
+		want     string   // запись отношения в виде Relation.String()
+		set      []string // ожидаемое множество; nil — не проверять
+	}{
+		{"pairs", "{(1, 2), (2, 3)}", nil, FormatPairs, "{(1, 2), (2, 3)}", []string{"1", "2", "3"}},
+		{"bare pairs", "(a, b), (b, a)", nil, FormatPairs, "{(a, b), (b, a)}", []string{"a", "b"}},
+		{"empty set", "{}", []string{"1", "2"}, FormatPairs, "{}", []string{"1", "2"}},
+		{"empty set symbol", "∅", []string{"1"}, FormatPairs, "{}", nil},
+		{"pairs on known set", "{(2, 1)}", []string{"1", "2", "3"}, FormatPairs, "{(2, 1)}", []string{"1", "2", "3"}},
+		{"pairs with domain", "A = {a, b, c}\n{(c, a)}", nil, FormatPairs, "{(c, a)}", []string{"a", "b", "c"}},
+		{"matrix", "110\n011\n001", nil, FormatMatrix, "{(1, 1), (1, 2), (2, 2), (2, 3), (3, 3)}", []string{"1", "2", "3"}},
+		{"matrix with separators", "[1, 0]\n[0, 1]", nil, FormatMatrix, "{(1, 1), (2, 2)}", nil},
+		{"matrix on domain", "A = {a, b}\n01\n10", nil, FormatMatrix, "{(a, b), (b, a)}", []string{"a", "b"}},
+		{"adjacency", "a: b, c\nb -> c\nc:", nil, FormatAdjacency, "{(a, b), (a, c), (b, c)}", []string{"a", "b", "c"}},
+		{"builder divides", "{(x, y) ∈ A×A | x divides y}, A = {1..4}", nil, FormatPredicate,
+			"{(1, 1), (1, 2), (1, 3), (1, 4), (2, 2), (2, 4), (3, 3), (4, 4)}", nil},
+		{"bare predicate", "x < y на A = {1..3}", nil, FormatPredicate, "{(1, 2), (1, 3), (2, 3)}", nil},
+		{"congruence", "x ≡ y (mod 2) where A = {1..4}", nil, FormatPredicate,
+			"{(1, 1), (1, 3), (2, 2), (2, 4), (3, 1), (3, 3), (4, 2), (4, 4)}", nil},
+		{"connectives", "x < y and not y = 3 or x = y, A = {1..3}", nil, FormatPredicate,
+			"{(1, 1), (1, 2), (2, 2), (3, 3)}", nil},
+		{"russian connectives", "x ≠ y и (x + y) mod 2 = 0 где A = {1..4}", nil, FormatPredicate,
+			"{(1, 3), (2, 4), (3, 1), (4, 2)}", nil},
+		{"negative remainder", "(x - y) % 3 = 1; A = {1..3}", nil, FormatPredicate,
+			"{(1, 3), (2, 1), (3, 2)}", nil},
+		{"predicate on known set", "x > y", []string{"1", "2"}, FormatPredicate, "{(2, 1)}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.input, tt.elements)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if res.Format != tt.format {
+				t.Errorf("format = %s, want %s", res.Format, tt.format)
+			}
+			if got := res.Relation.String(); got != tt.want {
+				t.Errorf("relation = %s, want %s", got, tt.want)
+			}
+			if tt.set != nil {
+				if got := res.Relation.Set().Elements(); !slices.Equal(got, tt.set) {
+					t.Errorf("set = %v, want %v", got, tt.set)
+				}
+			}
+		})
+	}
+}
+
+// Запись, полученная из Relation.String(), разбирается обратно в то же отношение
+func TestParseRoundTrip(t *testing.T) {
+	inputs := []string{
+		"{(1, 2), (2, 3), (3, 1)}",
+		"x divides y, A = {1..6}",
+		"a: a, b\nb: c\nc:",
+	}
+	for _, input := range inputs {
+		first, err := Parse(input, nil)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		elements := first.Relation.Set().Elements()
+		second, err := Parse(first.Relation.String(), elements)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", first.Relation.String(), err)
+		}
+		if !first.Relation.Equal(second.Relation) {
+			t.Errorf("%q: round trip gave %s, want %s", input, second.Relation, first.Relation)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		elements []string
+		line     int
+		column   int
+	}{
+		{"empty", "", nil, 1, 1},
+		{"unclosed pair", "{(1, 2}", nil, 1, 7},
+		{"unknown element", "{(1, 4)}", []string{"1", "2", "3"}, 1, 6},
+		{"ragged matrix", "10\n1", nil, 2, 1},
+		{"matrix rows on domain", "A = {1..3}\n10\n01", nil, 2, 1},
+		{"predicate without set", "x < y", nil, 1, 1},
+		{"unknown variable", "x < z, A = {1..3}", nil, 1, 5},
+		{"zero modulus", "x ≡ y (mod 0), A = {1..3}", nil, 1, 12},
+		{"range too large", "x < y, A = {1..1000}", nil, 1, 14},
+		{"range overflowing int64", "x < y, A = {0..9223372036854775807}", nil, 1, 14},
+		{"missing brace", "{(x, y) ∈ A×A | x < y", []string{"1", "2"}, 1, 22},
+		// Регрессия: запятые без нулей и единиц принимались за матрицу без строк
+		{"only comma", ",", nil, 1, 1},
+		{"comma after domain", "A={1}\n,", nil, 2, 1},
+		{"only brackets", "[];", nil, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input, tt.elements)
+			var syntax *SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Fatalf("Parse(%q) error = %v, want *SyntaxError", tt.input, err)
+			}
+			if syntax.Line != tt.line || syntax.Column != tt.column {
+				t.Errorf("Parse(%q) error at %d:%d, want %d:%d (%v)", tt.input, syntax.Line, syntax.Column, tt.line, tt.column, err)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"{(1, 2)}", "10\n01", "a: b", "x | y, A = {1..5}", ",", "A={1}\n,"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		Parse(input, nil)
+		Parse(input, []string{"1", "2", "3"})
+	})
+}
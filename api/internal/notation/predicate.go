@@ -0,0 +1,428 @@
+package notation
+
+import (
+	"api/internal/relation"
+	"strconv"
+)
+
+// Предикат задает отношение условием на паре (x, y) целых чисел:
+//
+//	{(x, y) ∈ A×A | x divides y}, A = {1..12}
+//	x ≡ y (mod 3) на A = {1..12}
+//	x < y and (x + y) % 2 = 0, A = {-3..3}
+//
+// Условия соединяются словами and/or/not (и/или/не) или знаками && || ! ∧ ∨ ¬,
+// сравниваются знаками = ≠ < ≤ > ≥ (и их ASCII-вариантами), «x divides y»,
+// «x | y» и «x ∣ y» означают, что x делит y. Арифметика — + - * / % и mod.
+
+// isBuilder: после первой пары в фигурных скобках идет ∈, in, | или :
+func isBuilder(tokens []token) bool {
+	tokens = skipNewlines(tokens)
+	if len(tokens) < 7 || !tokens[1].is("(") || tokens[2].kind != tWord ||
+		!tokens[3].is(",") || tokens[4].kind != tWord || !tokens[5].is(")") {
+		return false
+	}
+	next := tokens[6]
+	return next.is("∈") || next.is("|") || next.is(":") || next.isWord("in")
+}
+
+// parseBuilder разбирает {(x, y) ∈ A×A | условие}
+func (p *parser) parseBuilder(tokens []token, domain *domainDecl) ([]located, error) {
+	tokens = skipNewlines(tokens)
+	vars := [2]string{tokens[2].text, tokens[4].text}
+	if vars[0] == vars[1] {
+		return nil, p.errorf(tokens[4].off, "pair variables must differ")
+	}
+
+	// Принадлежность (∈ A×A) пропускается: множество одно на всю запись
+	i := 6
+	for i < len(tokens) && !tokens[i].is("|") && !tokens[i].is(":") {
+		i++
+	}
+	if i == len(tokens) {
+		return nil, p.errorAt(tokens, i, "expected '|' before the condition")
+	}
+	last := len(tokens) - 1
+	if !tokens[last].is("}") || last == i {
+		return nil, p.errorAt(tokens, len(tokens), "expected '}' to close the set")
+	}
+	if last == i+1 {
+		return nil, p.errorf(tokens[last].off, "expected a condition")
+	}
+	return p.parsePredicate(tokens[i+1:last], vars, domain)
+}
+
+// parsePredicate вычисляет условие на всех парах множества
+func (p *parser) parsePredicate(tokens []token, vars [2]string, domain *domainDecl) ([]located, error) {
+	tokens = skipNewlines(tokens)
+	if len(tokens) == 0 {
+		return nil, p.errorf(len(p.src), "expected a condition")
+	}
+	if domain == nil {
+		return nil, p.errorf(tokens[0].off, "a condition needs a set, for example A = {1..12}")
+	}
+	if domain.integers == nil {
+		return nil, p.errorAt(tokens, 0, "a condition needs a set of integers")
+	}
+
+	e := &exprParser{p: p, tokens: tokens, vars: vars}
+	cond, err := e.or()
+	if err != nil {
+		return nil, err
+	}
+	if e.i < len(tokens) {
+		return nil, p.errorf(tokens[e.i].off, "unexpected %q", tokens[e.i].text)
+	}
+	if !cond.boolean {
+		return nil, p.errorf(tokens[0].off, "expected a condition, got a number")
+	}
+
+	var pairs []located
+	for a, x := range domain.integers {
+		for b, y := range domain.integers {
+			v, err := cond.eval([2]int64{x, y})
+			if err != nil {
+				return nil, err
+			}
+			if v != 0 {
+				pairs = append(pairs, located{pair: relation.Pair{From: domain.elements[a], To: domain.elements[b]}})
+			}
+		}
+	}
+	return pairs, nil
+}
+
+// expr — скомпилированное выражение; условия возвращают 0 или 1
+type expr struct {
+	boolean bool
+	off     int
+	eval    func(env [2]int64) (int64, error)
+}
+
+type exprParser struct {
+	p      *parser
+	tokens []token
+	i      int
+	vars   [2]string
+}
+
+func (e *exprParser) peek() token {
+	if e.i < len(e.tokens) {
+		return e.tokens[e.i]
+	}
+	return token{kind: tNewline, off: len(e.p.src)}
+}
+
+func (e *exprParser) errorf(format string, args ...any) error {
+	return e.p.errorAt(e.tokens, e.i, format, args...)
+}
+
+func (e *exprParser) or() (*expr, error) {
+	left, err := e.and()
+	if err != nil {
+		return nil, err
+	}
+	for t := e.peek(); t.isWord("or", "или") || t.is("||") || t.is("∨"); t = e.peek() {
+		e.i++
+		right, err := e.and()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = e.logical(t, left, right, func(a, b bool) bool { return a || b }); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (e *exprParser) and() (*expr, error) {
+	left, err := e.not()
+	if err != nil {
+		return nil, err
+	}
+	for t := e.peek(); t.isWord("and", "и") || t.is("&&") || t.is("∧"); t = e.peek() {
+		e.i++
+		right, err := e.not()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = e.logical(t, left, right, func(a, b bool) bool { return a && b }); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (e *exprParser) logical(op token, left, right *expr, f func(a, b bool) bool) (*expr, error) {
+	if !left.boolean || !right.boolean {
+		return nil, e.p.errorf(op.off, "%q joins conditions, not numbers", op.text)
+	}
+	return &expr{boolean: true, off: left.off, eval: func(env [2]int64) (int64, error) {
+		a, err := left.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		b, err := right.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		return truth(f(a != 0, b != 0)), nil
+	}}, nil
+}
+
+func (e *exprParser) not() (*expr, error) {
+	t := e.peek()
+	if !t.isWord("not", "не") && !t.is("!") && !t.is("¬") {
+		return e.comparison()
+	}
+	e.i++
+	operand, err := e.not()
+	if err != nil {
+		return nil, err
+	}
+	if !operand.boolean {
+		return nil, e.p.errorf(t.off, "%q applies to a condition, not a number", t.text)
+	}
+	return &expr{boolean: true, off: t.off, eval: func(env [2]int64) (int64, error) {
+		v, err := operand.eval(env)
+		return truth(v == 0), err
+	}}, nil
+}
+
+// Сравнения: значение — истинно ли условие для пары чисел
+var comparisons = map[string]func(a, b int64) bool{
+	"=":       func(a, b int64) bool { return a == b },
+	"==":      func(a, b int64) bool { return a == b },
+	"≠":       func(a, b int64) bool { return a != b },
+	"!=":      func(a, b int64) bool { return a != b },
+	"<":       func(a, b int64) bool { return a < b },
+	">":       func(a, b int64) bool { return a > b },
+	"<=":      func(a, b int64) bool { return a <= b },
+	"≤":       func(a, b int64) bool { return a <= b },
+	">=":      func(a, b int64) bool { return a >= b },
+	"≥":       func(a, b int64) bool { return a >= b },
+	"|":       divides,
+	"∣":       divides,
+	"divides": divides,
+	"делит":   divides,
+}
+
+func (e *exprParser) comparison() (*expr, error) {
+	left, err := e.sum(true)
+	if err != nil {
+		return nil, err
+	}
+	op := e.peek()
+	if op.is("≡") {
+		return e.congruence(left)
+	}
+	compare, ok := comparisons[op.text]
+	if !ok || op.kind == tNewline || left.boolean {
+		return left, nil
+	}
+	e.i++
+	right, err := e.sum(true)
+	if err != nil {
+		return nil, err
+	}
+	if right.boolean {
+		return nil, e.p.errorf(op.off, "%q compares numbers, not conditions", op.text)
+	}
+	return &expr{boolean: true, off: left.off, eval: func(env [2]int64) (int64, error) {
+		a, b, err := both(left, right, env)
+		return truth(compare(a, b)), err
+	}}, nil
+}
+
+// congruence разбирает «a ≡ b (mod k)» или «a ≡ b mod k»
+func (e *exprParser) congruence(left *expr) (*expr, error) {
+	op := e.peek()
+	e.i++
+	right, err := e.sum(false)
+	if err != nil {
+		return nil, err
+	}
+
+	parens := e.peek().is("(")
+	if parens {
+		e.i++
+	}
+	if !e.peek().isWord("mod") {
+		return nil, e.errorf("expected 'mod' after %q", op.text)
+	}
+	e.i++
+	modOff := e.peek().off
+	modulus, err := e.sum(false)
+	if err != nil {
+		return nil, err
+	}
+	if parens {
+		if !e.peek().is(")") {
+			return nil, e.errorf("expected ')' after the modulus")
+		}
+		e.i++
+	}
+	if left.boolean || right.boolean || modulus.boolean {
+		return nil, e.p.errorf(op.off, "%q compares numbers, not conditions", op.text)
+	}
+
+	return &expr{boolean: true, off: left.off, eval: func(env [2]int64) (int64, error) {
+		a, b, err := both(left, right, env)
+		if err != nil {
+			return 0, err
+		}
+		k, err := modulus.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		if k == 0 {
+			return 0, e.p.errorf(modOff, "modulus is zero")
+		}
+		return truth((a-b)%k == 0), nil
+	}}, nil
+}
+
+// sum и term — арифметика; withMod разрешает бинарный mod, который внутри
+// сравнения по модулю относится к самому сравнению
+func (e *exprParser) sum(withMod bool) (*expr, error) {
+	left, err := e.term(withMod)
+	if err != nil {
+		return nil, err
+	}
+	for t := e.peek(); t.is("+") || t.is("-"); t = e.peek() {
+		e.i++
+		right, err := e.term(withMod)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = e.arithmetic(t, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (e *exprParser) term(withMod bool) (*expr, error) {
+	left, err := e.unary(withMod)
+	if err != nil {
+		return nil, err
+	}
+	for t := e.peek(); t.is("*") || t.is("/") || t.is("%") || withMod && t.isWord("mod"); t = e.peek() {
+		e.i++
+		right, err := e.unary(withMod)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = e.arithmetic(t, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (e *exprParser) arithmetic(op token, left, right *expr) (*expr, error) {
+	if left.boolean || right.boolean {
+		return nil, e.p.errorf(op.off, "%q applies to numbers, not conditions", op.text)
+	}
+	return &expr{off: left.off, eval: func(env [2]int64) (int64, error) {
+		a, b, err := both(left, right, env)
+		if err != nil {
+			return 0, err
+		}
+		switch op.text {
+		case "+":
+			return a + b, nil
+		case "-":
+			return a - b, nil
+		case "*":
+			return a * b, nil
+		}
+		if b == 0 {
+			return 0, e.p.errorf(op.off, "division by zero")
+		}
+		if op.text == "/" {
+			return a / b, nil
+		}
+		// Остаток всегда неотрицателен, как в записи a mod b
+		r := a % b
+		if r < 0 {
+			r += max(b, -b)
+		}
+		return r, nil
+	}}, nil
+}
+
+func (e *exprParser) unary(withMod bool) (*expr, error) {
+	t := e.peek()
+	if !t.is("-") {
+		return e.primary()
+	}
+	e.i++
+	operand, err := e.unary(withMod)
+	if err != nil {
+		return nil, err
+	}
+	if operand.boolean {
+		return nil, e.p.errorf(t.off, "'-' applies to a number, not a condition")
+	}
+	return &expr{off: t.off, eval: func(env [2]int64) (int64, error) {
+		v, err := operand.eval(env)
+		return -v, err
+	}}, nil
+}
+
+func (e *exprParser) primary() (*expr, error) {
+	t := e.peek()
+	switch {
+	case t.is("("):
+		e.i++
+		inner, err := e.or()
+		if err != nil {
+			return nil, err
+		}
+		if !e.peek().is(")") {
+			return nil, e.errorf("expected ')'")
+		}
+		e.i++
+		return inner, nil
+	case t.kind == tWord:
+		if v, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			e.i++
+			return &expr{off: t.off, eval: func([2]int64) (int64, error) { return v, nil }}, nil
+		}
+		for k, name := range e.vars {
+			if t.text == name {
+				e.i++
+				return &expr{off: t.off, eval: func(env [2]int64) (int64, error) { return env[k], nil }}, nil
+			}
+		}
+		return nil, e.errorf("unknown name %q, expected %s or %s", t.text, e.vars[0], e.vars[1])
+	case t.kind == tNewline:
+		return nil, e.errorf("unexpected end of the condition")
+	}
+	return nil, e.errorf("unexpected %q", t.text)
+}
+
+func both(left, right *expr, env [2]int64) (int64, int64, error) {
+	a, err := left.eval(env)
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := right.eval(env)
+	return a, b, err
+}
+
+// divides: a делит b; ноль делит только ноль
+func divides(a, b int64) bool {
+	if a == 0 {
+		return b == 0
+	}
+	return b%a == 0
+}
+
+func truth(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package relation
+
+import "fmt"
+
+// Class — вид отношения, определяемый набором свойств
+type Class string
+
+const (
+	Equivalence  Class = "equivalence"   // рефлексивно, симметрично, транзитивно
+	PartialOrder Class = "partial_order" // рефлексивно, антисимметрично, транзитивно
+	StrictOrder  Class = "strict_order"  // иррефлексивно, транзитивно (отсюда асимметрично)
+	LinearOrder  Class = "linear_order"  // частичный порядок, в котором сравнимы любые элементы
+	Function     Class = "function"      // каждому элементу сопоставлен ровно один образ
+)
+
+// Classes — все виды в порядке вывода
+var Classes = []Class{Equivalence, PartialOrder, StrictOrder, LinearOrder, Function}
+
+func ParseClass(s string) (Class, error) {
+	for _, c := range Classes {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown relation class %q", s)
+}
+
+// Is проверяет, относится ли отношение к виду
+func (r *Relation) Is(c Class) bool {
+	switch c {
+	case Equivalence:
+		return r.IsEquivalence()
+	case PartialOrder:
+		return r.IsPartialOrder()
+	case StrictOrder:
+		return r.IsStrictOrder()
+	case LinearOrder:
+		return r.IsLinearOrder()
+	case Function:
+		return r.IsFunction()
+	default:
+		return false
+	}
+}
+
+// Classify возвращает все виды, к которым относится отношение
+func (r *Relation) Classify() []Class {
+	classes := []Class{}
+	for _, c := range Classes {
+		if r.Is(c) {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+func (r *Relation) IsEquivalence() bool {
+	return r.IsReflexive() && r.IsSymmetric() && r.IsTransitive()
+}
+
+func (r *Relation) IsPartialOrder() bool {
+	return r.IsReflexive() && r.IsAntisymmetric() && r.IsTransitive()
+}
+
+func (r *Relation) IsStrictOrder() bool {
+	return r.IsIrreflexive() && r.IsTransitive()
+}
+
+func (r *Relation) IsLinearOrder() bool {
+	return r.IsPartialOrder() && r.IsConnex()
+}
+
+// IsFunction проверяет, что отношение задает всюду определенную функцию множества в себя
+func (r *Relation) IsFunction() bool {
+	for _, row := range r.matrix {
+		images := 0
+		for _, v := range row {
+			if v {
+				images++
+			}
+		}
+		if images != 1 {
+			return false
+		}
+	}
+	return true
+}
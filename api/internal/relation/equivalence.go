@@ -0,0 +1,128 @@
+package relation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNotEquivalence = errors.New("relation is not an equivalence")
+	ErrNotPartition   = errors.New("blocks do not form a partition of the set")
+)
+
+// EquivalenceClass возвращает класс [a] = {x | aRx} в порядке элементов множества
+func (r *Relation) EquivalenceClass(a string) ([]string, error) {
+	if !r.IsEquivalence() {
+		return nil, ErrNotEquivalence
+	}
+	i, ok := r.set.Index(a)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownElement, a)
+	}
+	return r.row(i), nil
+}
+
+// Quotient возвращает фактор-множество A/R — классы эквивалентности,
+// упорядоченные по первому элементу
+func (r *Relation) Quotient() ([][]string, error) {
+	if !r.IsEquivalence() {
+		return nil, ErrNotEquivalence
+	}
+
+	classes := [][]string{}
+	seen := make([]bool, r.set.Len())
+	for i := range r.matrix {
+		if seen[i] {
+			continue
+		}
+		class := r.row(i)
+		for _, e := range class {
+			j, _ := r.set.Index(e)
+			seen[j] = true
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// row возвращает элементы, с которыми связан i-й элемент
+func (r *Relation) row(i int) []string {
+	var elements []string
+	for j, v := range r.matrix[i] {
+		if v {
+			elements = append(elements, r.set.Element(j))
+		}
+	}
+	return elements
+}
+
+// NormalizePartition проверяет, что блоки непусты, не пересекаются и покрывают
+// множество, и упорядочивает элементы блоков и сами блоки по порядку множества
+func NormalizePartition(set *Set, blocks [][]string) ([][]string, error) {
+	seen := make(map[string]bool, set.Len())
+	result := make([][]string, 0, len(blocks))
+	for _, block := range blocks {
+		if len(block) == 0 {
+			return nil, fmt.Errorf("%w: empty block", ErrNotPartition)
+		}
+		normalized := make([]string, 0, len(block))
+		for _, e := range block {
+			e = strings.TrimSpace(e)
+			if !set.Contains(e) {
+				return nil, fmt.Errorf("%w %q", ErrUnknownElement, e)
+			}
+			if seen[e] {
+				return nil, fmt.Errorf("%w: element %q belongs to several blocks", ErrNotPartition, e)
+			}
+			seen[e] = true
+			normalized = append(normalized, e)
+		}
+		sort.Slice(normalized, func(i, j int) bool { return set.index[normalized[i]] < set.index[normalized[j]] })
+		result = append(result, normalized)
+	}
+
+	for _, e := range set.elements {
+		if !seen[e] {
+			return nil, fmt.Errorf("%w: element %q is not in any block", ErrNotPartition, e)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return set.index[result[i][0]] < set.index[result[j][0]] })
+	return result, nil
+}
+
+// FromPartition строит отношение эквивалентности, классы которого — блоки разбиения
+func FromPartition(set *Set, blocks [][]string) (*Relation, error) {
+	blocks, err := NormalizePartition(set, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	r := New(set)
+	for _, block := range blocks {
+		for _, a := range block {
+			for _, b := range block {
+				i, j, _ := r.indices(a, b)
+				r.matrix[i][j] = true
+			}
+		}
+	}
+	return r, nil
+}
+
+// CommonBlocks считает блоки, которые есть в обоих нормализованных разбиениях
+func CommonBlocks(a, b [][]string) int {
+	keys := make(map[string]bool, len(a))
+	for _, block := range a {
+		keys[strings.Join(block, "\x00")] = true
+	}
+	common := 0
+	for _, block := range b {
+		if keys[strings.Join(block, "\x00")] {
+			common++
+		}
+	}
+	return common
+}
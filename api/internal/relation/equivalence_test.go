@@ -0,0 +1,91 @@
+package relation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQuotient(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation *Relation
+		want     [][]string
+	}{
+		{"identity", build(t, "a b c", "a-a", "b-b", "c-c"), [][]string{{"a"}, {"b"}, {"c"}}},
+		{"full", build(t, "a b", "a-a", "a-b", "b-a", "b-b"), [][]string{{"a", "b"}}},
+		// Сравнение по модулю 2 на {1, 2, 3, 4}
+		{"parity", build(t, "1 2 3 4", "1-1", "1-3", "3-1", "3-3", "2-2", "2-4", "4-2", "4-4"),
+			[][]string{{"1", "3"}, {"2", "4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.relation.Quotient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Quotient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	parity := tests[2].relation
+	class, err := parity.EquivalenceClass("4")
+	if err != nil || !reflect.DeepEqual(class, []string{"2", "4"}) {
+		t.Errorf("EquivalenceClass(4) = %v, %v, want [2 4]", class, err)
+	}
+	if _, err := parity.EquivalenceClass("5"); !errors.Is(err, ErrUnknownElement) {
+		t.Errorf("EquivalenceClass(5): error = %v, want %v", err, ErrUnknownElement)
+	}
+
+	notEquivalence := build(t, "a b", "a-a", "a-b")
+	if _, err := notEquivalence.Quotient(); !errors.Is(err, ErrNotEquivalence) {
+		t.Errorf("Quotient of a non-equivalence: error = %v, want %v", err, ErrNotEquivalence)
+	}
+	if _, err := notEquivalence.EquivalenceClass("a"); !errors.Is(err, ErrNotEquivalence) {
+		t.Errorf("EquivalenceClass of a non-equivalence: error = %v, want %v", err, ErrNotEquivalence)
+	}
+}
+
+func TestFromPartition(t *testing.T) {
+	set, _ := NewSet("1", "2", "3", "4")
+
+	r, err := FromPartition(set, [][]string{{"4", " 2"}, {"3", "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsEquivalence() {
+		t.Fatalf("FromPartition built %s, which is not an equivalence", r)
+	}
+	classes, _ := r.Quotient()
+	if want := [][]string{{"1", "3"}, {"2", "4"}}; !reflect.DeepEqual(classes, want) {
+		t.Errorf("classes = %v, want %v", classes, want)
+	}
+
+	errorCases := []struct {
+		name   string
+		blocks [][]string
+		want   error
+	}{
+		{"empty block", [][]string{{"1", "2", "3", "4"}, {}}, ErrNotPartition},
+		{"overlap", [][]string{{"1", "2"}, {"2", "3", "4"}}, ErrNotPartition},
+		{"not covering", [][]string{{"1", "2"}, {"3"}}, ErrNotPartition},
+		{"unknown element", [][]string{{"1", "2"}, {"3", "4", "5"}}, ErrUnknownElement},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromPartition(set, tt.blocks); !errors.Is(err, tt.want) {
+				t.Errorf("FromPartition(%v): error = %v, want %v", tt.blocks, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonBlocks(t *testing.T) {
+	a := [][]string{{"1", "3"}, {"2"}, {"4"}}
+	b := [][]string{{"1", "3"}, {"2", "4"}}
+	if got := CommonBlocks(a, b); got != 1 {
+		t.Errorf("CommonBlocks = %d, want 1", got)
+	}
+}
@@ -0,0 +1,245 @@
+package relation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Witness — контрпример к свойству: пары, которые есть в отношении (Present),
+// и пары, которых в нем нет (Missing), вместе нарушающие свойство
+type Witness struct {
+	Present []Pair `json:"present"`
+	Missing []Pair `json:"missing"`
+}
+
+// Explanation — результат проверки свойства или вида отношения с объяснением
+// на русском языке; при нарушении Witness содержит контрпример
+type Explanation struct {
+	Condition string   `json:"condition"`
+	Holds     bool     `json:"holds"`
+	Witness   *Witness `json:"witness,omitempty"`
+	Text      string   `json:"text"`
+}
+
+// Формулировки свойства: когда оно выполняется и когда нарушается
+var propertyTitles = map[Property][2]string{
+	Reflexive:     {"Отношение рефлексивно: для каждого элемента x есть пара (x, x)", "Отношение не рефлексивно"},
+	Irreflexive:   {"Отношение иррефлексивно: ни для какого элемента x нет пары (x, x)", "Отношение не иррефлексивно"},
+	Symmetric:     {"Отношение симметрично: для каждой пары (x, y) есть обратная пара (y, x)", "Отношение не симметрично"},
+	Antisymmetric: {"Отношение антисимметрично: пары (x, y) и (y, x) вместе встречаются только при x = y", "Отношение не антисимметрично"},
+	Asymmetric:    {"Отношение асимметрично: ни для какой пары (x, y) нет обратной пары (y, x)", "Отношение не асимметрично"},
+	Transitive:    {"Отношение транзитивно: из пар (x, y) и (y, z) всегда следует пара (x, z)", "Отношение не транзитивно"},
+	Connex:        {"Отношение полное: любые два элемента x и y связаны парой (x, y) или (y, x)", "Отношение не полное"},
+}
+
+// Названия видов отношения в родительном падеже: «не является ...»
+var classTitles = map[Class]string{
+	Equivalence:  "эквивалентностью",
+	PartialOrder: "частичным порядком",
+	StrictOrder:  "строгим порядком",
+	LinearOrder:  "линейным порядком",
+	Function:     "функцией",
+}
+
+// Свойства, из которых складывается вид отношения (кроме функции)
+var classProperties = map[Class][]Property{
+	Equivalence:  {Reflexive, Symmetric, Transitive},
+	PartialOrder: {Reflexive, Antisymmetric, Transitive},
+	StrictOrder:  {Irreflexive, Transitive},
+	LinearOrder:  {Reflexive, Antisymmetric, Transitive, Connex},
+}
+
+// Counterexample возвращает первый найденный контрпример к свойству
+// в порядке элементов множества или nil, если свойство выполняется
+func (r *Relation) Counterexample(p Property) *Witness {
+	e := r.set.Element
+	for i, row := range r.matrix {
+		for j, v := range row {
+			switch p {
+			case Reflexive:
+				if i == j && !v {
+					return &Witness{Present: []Pair{}, Missing: []Pair{{e(i), e(i)}}}
+				}
+			case Irreflexive:
+				if i == j && v {
+					return &Witness{Present: []Pair{{e(i), e(i)}}, Missing: []Pair{}}
+				}
+			case Symmetric:
+				if v && !r.matrix[j][i] {
+					return &Witness{Present: []Pair{{e(i), e(j)}}, Missing: []Pair{{e(j), e(i)}}}
+				}
+			case Antisymmetric:
+				if i != j && v && r.matrix[j][i] {
+					return &Witness{Present: []Pair{{e(i), e(j)}, {e(j), e(i)}}, Missing: []Pair{}}
+				}
+			case Asymmetric:
+				if v && r.matrix[j][i] {
+					present := []Pair{{e(i), e(j)}}
+					if i != j {
+						present = append(present, Pair{e(j), e(i)})
+					}
+					return &Witness{Present: present, Missing: []Pair{}}
+				}
+			case Transitive:
+				if !v {
+					continue
+				}
+				for k, w := range r.matrix[j] {
+					if w && !r.matrix[i][k] {
+						return &Witness{Present: []Pair{{e(i), e(j)}, {e(j), e(k)}}, Missing: []Pair{{e(i), e(k)}}}
+					}
+				}
+			case Connex:
+				if j >= i && !v && !r.matrix[j][i] {
+					missing := []Pair{{e(i), e(j)}}
+					if i != j {
+						missing = append(missing, Pair{e(j), e(i)})
+					}
+					return &Witness{Present: []Pair{}, Missing: missing}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ExplainProperty проверяет свойство и объясняет результат
+func (r *Relation) ExplainProperty(p Property) Explanation {
+	titles := propertyTitles[p]
+	w := r.Counterexample(p)
+	if w == nil {
+		return Explanation{Condition: string(p), Holds: true, Text: titles[0]}
+	}
+	return Explanation{Condition: string(p), Witness: w, Text: titles[1] + ": " + witnessReason(p, w)}
+}
+
+// witnessReason описывает контрпример словами
+func witnessReason(p Property, w *Witness) string {
+	switch p {
+	case Reflexive:
+		return fmt.Sprintf("нет пары %s", w.Missing[0])
+	case Irreflexive:
+		return fmt.Sprintf("есть пара %s", w.Present[0])
+	case Symmetric:
+		return fmt.Sprintf("есть пара %s, но нет обратной пары %s", w.Present[0], w.Missing[0])
+	case Antisymmetric:
+		a, b := w.Present[0].From, w.Present[0].To
+		return fmt.Sprintf("есть пары %s и %s, хотя %s ≠ %s", w.Present[0], w.Present[1], a, b)
+	case Asymmetric:
+		if len(w.Present) == 1 {
+			return fmt.Sprintf("есть пара %s, а она обратна сама себе", w.Present[0])
+		}
+		return fmt.Sprintf("есть пары %s и %s", w.Present[0], w.Present[1])
+	case Transitive:
+		return fmt.Sprintf("есть пары %s и %s, но нет пары %s", w.Present[0], w.Present[1], w.Missing[0])
+	case Connex:
+		if len(w.Missing) == 1 {
+			return fmt.Sprintf("нет пары %s", w.Missing[0])
+		}
+		return fmt.Sprintf("элементы %s и %s несравнимы — нет ни пары %s, ни пары %s",
+			w.Missing[0].From, w.Missing[0].To, w.Missing[0], w.Missing[1])
+	default:
+		return ""
+	}
+}
+
+// ExplainClass проверяет вид отношения. Если отношение к нему не относится,
+// объяснение и контрпример берутся у первого нарушенного свойства вида.
+func (r *Relation) ExplainClass(c Class) Explanation {
+	if c == Function {
+		return r.explainFunction()
+	}
+
+	title := classTitles[c]
+
+	for _, p := range classProperties[c] {
+		if ex := r.ExplainProperty(p); !ex.Holds {
+			return Explanation{
+				Condition: string(c),
+				Witness:   ex.Witness,
+				Text:      "Отношение не является " + title + ". " + ex.Text,
+			}
+		}
+	}
+
+	names := make([]string, 0, len(classProperties[c]))
+	for _, p := range classProperties[c] {
+		names = append(names, propertyAdjectives[p])
+	}
+	return Explanation{
+		Condition: string(c),
+		Holds:     true,
+		Text:      "Отношение является " + title + ": оно " + strings.Join(names, ", "),
+	}
+}
+
+// Краткие прилагательные для перечисления свойств вида
+var propertyAdjectives = map[Property]string{
+	Reflexive:     "рефлексивно",
+	Irreflexive:   "иррефлексивно",
+	Symmetric:     "симметрично",
+	Antisymmetric: "антисимметрично",
+	Asymmetric:    "асимметрично",
+	Transitive:    "транзитивно",
+	Connex:        "полно",
+}
+
+// explainFunction ищет элемент, у которого нет образа или больше одного образа
+func (r *Relation) explainFunction() Explanation {
+	for i, row := range r.matrix {
+		var images []Pair
+		for j, v := range row {
+			if v {
+				images = append(images, Pair{r.set.Element(i), r.set.Element(j)})
+			}
+		}
+
+		x := r.set.Element(i)
+		switch {
+		case len(images) == 0:
+			return Explanation{
+				Condition: string(Function),
+				Witness:   &Witness{Present: []Pair{}, Missing: []Pair{}},
+				Text:      fmt.Sprintf("Отношение не является функцией: у элемента %s нет образа — нет ни одной пары (%s, y)", x, x),
+			}
+		case len(images) > 1:
+			targets := make([]string, len(images))
+			for k, p := range images {
+				targets[k] = p.To
+			}
+			return Explanation{
+				Condition: string(Function),
+				Witness:   &Witness{Present: images, Missing: []Pair{}},
+				Text:      fmt.Sprintf("Отношение не является функцией: у элемента %s несколько образов: %s", x, strings.Join(targets, ", ")),
+			}
+		}
+	}
+	return Explanation{
+		Condition: string(Function),
+		Holds:     true,
+		Text:      "Отношение является функцией: у каждого элемента ровно один образ",
+	}
+}
+
+// Explain проверяет условие по названию свойства или вида отношения
+func (r *Relation) Explain(condition string) (Explanation, error) {
+	if p, err := ParseProperty(condition); err == nil {
+		return r.ExplainProperty(p), nil
+	}
+	if c, err := ParseClass(condition); err == nil {
+		return r.ExplainClass(c), nil
+	}
+	return Explanation{}, fmt.Errorf("unknown relation property or class %q", condition)
+}
+
+// ExplainAll проверяет все свойства, затем все виды отношения
+func (r *Relation) ExplainAll() []Explanation {
+	explanations := make([]Explanation, 0, len(Properties)+len(Classes))
+	for _, p := range Properties {
+		explanations = append(explanations, r.ExplainProperty(p))
+	}
+	for _, c := range Classes {
+		explanations = append(explanations, r.ExplainClass(c))
+	}
+	return explanations
+}
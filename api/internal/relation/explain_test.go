@@ -0,0 +1,67 @@
+package relation
+
+import "testing"
+
+// Контрпример есть ровно тогда, когда свойство нарушено
+func TestCounterexampleExists(t *testing.T) {
+	relations := []*Relation{
+		build(t, "a b"),
+		build(t, "a b", "a-a", "b-b"),
+		build(t, "1 2 3", "1-2", "1-3", "2-3"),
+		build(t, "a b c", "a-b", "b-c"),
+		build(t, "a b", "a-b", "b-a"),
+	}
+	for _, r := range relations {
+		for _, p := range Properties {
+			if w := r.Counterexample(p); (w == nil) != r.Satisfies(p) {
+				t.Errorf("%s: Counterexample(%s) = %v, property holds = %v", r, p, w, r.Satisfies(p))
+			}
+		}
+	}
+}
+
+// Контрпример действительно нарушает свойство: пары Present есть в отношении,
+// пар Missing нет
+func TestCounterexample(t *testing.T) {
+	r := build(t, "a b c", "a-b", "b-c", "c-c")
+	for _, p := range Properties {
+		w := r.Counterexample(p)
+		if w == nil {
+			continue
+		}
+		for _, pair := range w.Present {
+			if !r.Has(pair.From, pair.To) {
+				t.Errorf("%s: present pair %s is not in the relation", p, pair)
+			}
+		}
+		for _, pair := range w.Missing {
+			if r.Has(pair.From, pair.To) {
+				t.Errorf("%s: missing pair %s is in the relation", p, pair)
+			}
+		}
+	}
+
+	w := r.Counterexample(Transitive)
+	if w == nil || len(w.Missing) != 1 || w.Missing[0] != (Pair{"a", "c"}) {
+		t.Errorf("Counterexample(transitive) = %+v, want missing (a, c)", w)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	r := build(t, "a b", "a-b")
+	for _, condition := range []string{"reflexive", "total", "partial_order", "function"} {
+		ex, err := r.Explain(condition)
+		if err != nil {
+			t.Fatalf("Explain(%q): %v", condition, err)
+		}
+		if ex.Holds || ex.Text == "" {
+			t.Errorf("Explain(%q) = %+v, want a violated condition with text", condition, ex)
+		}
+	}
+	if _, err := r.Explain("connected"); err == nil {
+		t.Error("Explain(unknown condition): error = nil")
+	}
+	if got := len(r.ExplainAll()); got != len(Properties)+len(Classes) {
+		t.Errorf("len(ExplainAll()) = %d, want %d", got, len(Properties)+len(Classes))
+	}
+}
@@ -0,0 +1,321 @@
+package relation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Максимальная степень отношения: степени конечного отношения периодичны,
+// а каждая степень — отдельный шаг решения
+const MaxPower = 64
+
+var ErrDifferentSets = errors.New("relations are defined on different sets")
+
+// Operation — операция над отношениями
+type Operation string
+
+const (
+	OpReflexiveClosure   Operation = "reflexive_closure"
+	OpSymmetricClosure   Operation = "symmetric_closure"
+	OpTransitiveClosure  Operation = "transitive_closure"
+	OpEquivalenceClosure Operation = "equivalence_closure"
+	OpComposition        Operation = "composition"
+	OpInverse            Operation = "inverse"
+	OpComplement         Operation = "complement"
+	OpUnion              Operation = "union"
+	OpIntersection       Operation = "intersection"
+	OpPower              Operation = "power"
+)
+
+// Operations — все операции в порядке вывода
+var Operations = []Operation{
+	OpReflexiveClosure, OpSymmetricClosure, OpTransitiveClosure, OpEquivalenceClosure,
+	OpComposition, OpInverse, OpComplement, OpUnion, OpIntersection, OpPower,
+}
+
+func ParseOperation(s string) (Operation, error) {
+	for _, op := range Operations {
+		if string(op) == s {
+			return op, nil
+		}
+	}
+	return "", fmt.Errorf("unknown relation operation %q", s)
+}
+
+// Binary сообщает, нужен ли операции второй операнд
+func (op Operation) Binary() bool {
+	return op == OpComposition || op == OpUnion || op == OpIntersection
+}
+
+// Step — промежуточный шаг вычисления: что сделано, какие пары добавлены
+// и каким стало отношение после шага
+type Step struct {
+	Description string    `json:"description"`
+	Added       []Pair    `json:"added"`
+	Relation    *Relation `json:"relation"`
+}
+
+// Result — результат операции с шагами решения
+type Result struct {
+	Relation *Relation `json:"relation"`
+	Steps    []Step    `json:"steps"`
+}
+
+func (res *Result) step(description string, added []Pair) {
+	if added == nil {
+		added = []Pair{}
+	}
+	res.Steps = append(res.Steps, Step{Description: description, Added: added, Relation: res.Relation.Clone()})
+}
+
+// Apply выполняет операцию по названию; s нужен бинарным операциям, n — степени
+func Apply(op Operation, r, s *Relation, n int) (*Result, error) {
+	if op.Binary() && s == nil {
+		return nil, fmt.Errorf("operation %s requires a second relation", op)
+	}
+
+	switch op {
+	case OpReflexiveClosure:
+		return ReflexiveClosure(r), nil
+	case OpSymmetricClosure:
+		return SymmetricClosure(r), nil
+	case OpTransitiveClosure:
+		return TransitiveClosure(r), nil
+	case OpEquivalenceClosure:
+		return EquivalenceClosure(r), nil
+	case OpComposition:
+		return Compose(r, s)
+	case OpInverse:
+		return Inverse(r), nil
+	case OpComplement:
+		return Complement(r), nil
+	case OpUnion:
+		return Union(r, s)
+	case OpIntersection:
+		return Intersection(r, s)
+	case OpPower:
+		return Power(r, n)
+	default:
+		return nil, fmt.Errorf("unknown relation operation %q", op)
+	}
+}
+
+// ReflexiveClosure добавляет петли (a, a) всем элементам
+func ReflexiveClosure(r *Relation) *Result {
+	res := &Result{Relation: r.Clone()}
+	var added []Pair
+	for i := range res.Relation.matrix {
+		if !res.Relation.matrix[i][i] {
+			res.Relation.matrix[i][i] = true
+			e := r.set.Element(i)
+			added = append(added, Pair{e, e})
+		}
+	}
+	res.step("Добавляем пары (a, a) для всех элементов, у которых их нет", added)
+	return res
+}
+
+// SymmetricClosure добавляет к каждой паре (a, b) обратную (b, a)
+func SymmetricClosure(r *Relation) *Result {
+	res := &Result{Relation: r.Clone()}
+	var added []Pair
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if v && !res.Relation.matrix[j][i] {
+				res.Relation.matrix[j][i] = true
+				added = append(added, Pair{r.set.Element(j), r.set.Element(i)})
+			}
+		}
+	}
+	res.step("Для каждой пары (a, b) добавляем обратную пару (b, a)", added)
+	return res
+}
+
+// TransitiveClosure строит транзитивное замыкание алгоритмом Уоршелла:
+// на шаге k разрешаются пути через элемент k, шаг — одна промежуточная вершина
+func TransitiveClosure(r *Relation) *Result {
+	res := &Result{Relation: r.Clone()}
+	m := res.Relation.matrix
+	for k := range m {
+		var added []Pair
+		for i := range m {
+			if !m[i][k] {
+				continue
+			}
+			for j := range m {
+				if m[k][j] && !m[i][j] {
+					m[i][j] = true
+					added = append(added, Pair{r.set.Element(i), r.set.Element(j)})
+				}
+			}
+		}
+		via := r.set.Element(k)
+		res.step(fmt.Sprintf("Через элемент %s: если xR%s и %sRy, добавляем (x, y)", via, via, via), added)
+	}
+	return res
+}
+
+// EquivalenceClosure — наименьшая эквивалентность, содержащая отношение:
+// рефлексивное, затем симметричное, затем транзитивное замыкание
+func EquivalenceClosure(r *Relation) *Result {
+	reflexive := ReflexiveClosure(r)
+	symmetric := SymmetricClosure(reflexive.Relation)
+	transitive := TransitiveClosure(symmetric.Relation)
+
+	res := &Result{Relation: transitive.Relation}
+	for _, stage := range []struct {
+		title string
+		steps []Step
+	}{
+		{"Рефлексивное замыкание", reflexive.Steps},
+		{"Симметричное замыкание", symmetric.Steps},
+		{"Транзитивное замыкание", transitive.Steps},
+	} {
+		for _, step := range stage.steps {
+			step.Description = stage.title + ". " + step.Description
+			res.Steps = append(res.Steps, step)
+		}
+	}
+	return res
+}
+
+// Compose строит композицию R∘S = {(a, c) | существует b: aRb и bSc}.
+// Шаг решения — промежуточный элемент b.
+func Compose(r, s *Relation) (*Result, error) {
+	sm, err := alignedMatrix(r, s)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Relation: New(r.set)}
+	m := res.Relation.matrix
+	for b := range m {
+		var added []Pair
+		for a := range m {
+			if !r.matrix[a][b] {
+				continue
+			}
+			for c := range m {
+				if sm[b][c] && !m[a][c] {
+					m[a][c] = true
+					added = append(added, Pair{r.set.Element(a), r.set.Element(c)})
+				}
+			}
+		}
+		via := r.set.Element(b)
+		res.step(fmt.Sprintf("Через элемент %s: если xR%s и %sSy, добавляем (x, y)", via, via, via), added)
+	}
+	return res, nil
+}
+
+// Inverse строит обратное отношение R⁻¹ = {(b, a) | aRb}
+func Inverse(r *Relation) *Result {
+	res := &Result{Relation: New(r.set)}
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if v {
+				res.Relation.matrix[j][i] = true
+			}
+		}
+	}
+	res.step("Переворачиваем каждую пару: (a, b) → (b, a)", res.Relation.Pairs())
+	return res
+}
+
+// Complement строит дополнение до A×A
+func Complement(r *Relation) *Result {
+	res := &Result{Relation: New(r.set)}
+	for i, row := range r.matrix {
+		for j, v := range row {
+			res.Relation.matrix[i][j] = !v
+		}
+	}
+	res.step(fmt.Sprintf("Берем пары A×A, которых нет в R (|A×A| = %d)", r.set.Len()*r.set.Len()), res.Relation.Pairs())
+	return res
+}
+
+// Union строит объединение R ∪ S
+func Union(r, s *Relation) (*Result, error) {
+	sm, err := alignedMatrix(r, s)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Relation: r.Clone()}
+	var added []Pair
+	for i, row := range sm {
+		for j, v := range row {
+			if v && !res.Relation.matrix[i][j] {
+				res.Relation.matrix[i][j] = true
+				added = append(added, Pair{r.set.Element(i), r.set.Element(j)})
+			}
+		}
+	}
+	res.step("К парам R добавляем пары S, которых нет в R", added)
+	return res, nil
+}
+
+// Intersection строит пересечение R ∩ S
+func Intersection(r, s *Relation) (*Result, error) {
+	sm, err := alignedMatrix(r, s)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Relation: New(r.set)}
+	for i, row := range r.matrix {
+		for j, v := range row {
+			res.Relation.matrix[i][j] = v && sm[i][j]
+		}
+	}
+	res.step("Оставляем пары, которые есть и в R, и в S", res.Relation.Pairs())
+	return res, nil
+}
+
+// Power строит степень Rⁿ: R⁰ — тождественное отношение, Rᵏ = Rᵏ⁻¹∘R.
+// Шаг решения — очередная степень.
+func Power(r *Relation, n int) (*Result, error) {
+	if n < 0 || n > MaxPower {
+		return nil, fmt.Errorf("power must be between 0 and %d", MaxPower)
+	}
+
+	res := &Result{Relation: New(r.set)}
+	for i := range res.Relation.matrix {
+		res.Relation.matrix[i][i] = true
+	}
+	res.step("R⁰ — тождественное отношение: пары (a, a)", res.Relation.Pairs())
+
+	for k := 1; k <= n; k++ {
+		next, _ := Compose(res.Relation, r)
+		res.Relation = next.Relation
+		res.step(fmt.Sprintf("R%s = R%s∘R", superscript(k), superscript(k-1)), res.Relation.Pairs())
+	}
+	return res, nil
+}
+
+// alignedMatrix возвращает матрицу s в порядке элементов множества r
+func alignedMatrix(r, s *Relation) ([][]bool, error) {
+	if !r.set.Equal(s.set) {
+		return nil, fmt.Errorf("%w: %s and %s", ErrDifferentSets, r.set, s.set)
+	}
+
+	n := r.set.Len()
+	matrix := make([][]bool, n)
+	for i := range matrix {
+		matrix[i] = make([]bool, n)
+		for j := range matrix[i] {
+			matrix[i][j] = s.Has(r.set.Element(i), r.set.Element(j))
+		}
+	}
+	return matrix, nil
+}
+
+func superscript(n int) string {
+	digits := []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+	var b strings.Builder
+	for _, d := range fmt.Sprint(n) {
+		b.WriteRune(digits[d-'0'])
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package relation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnaryOperations(t *testing.T) {
+	path := build(t, "a b c", "a-b", "b-c")
+	tests := []struct {
+		name string
+		op   Operation
+		r    *Relation
+		want string
+	}{
+		{"reflexive closure", OpReflexiveClosure, path, "{(a, a), (a, b), (b, b), (b, c), (c, c)}"},
+		{"symmetric closure", OpSymmetricClosure, path, "{(a, b), (b, a), (b, c), (c, b)}"},
+		{"transitive closure", OpTransitiveClosure, path, "{(a, b), (a, c), (b, c)}"},
+		{"transitive closure of a cycle", OpTransitiveClosure, build(t, "a b c", "a-b", "b-c", "c-a"),
+			"{(a, a), (a, b), (a, c), (b, a), (b, b), (b, c), (c, a), (c, b), (c, c)}"},
+		{"equivalence closure", OpEquivalenceClosure, build(t, "a b c", "a-b"),
+			"{(a, a), (a, b), (b, a), (b, b), (c, c)}"},
+		{"inverse", OpInverse, path, "{(b, a), (c, b)}"},
+		{"complement", OpComplement, build(t, "a b", "a-a", "a-b"), "{(b, a), (b, b)}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Apply(tt.op, tt.r, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.Relation.String(); got != tt.want {
+				t.Errorf("%s = %s, want %s", tt.op, got, tt.want)
+			}
+			if len(res.Steps) == 0 {
+				t.Errorf("%s has no solution steps", tt.op)
+			}
+		})
+	}
+
+	// Замыкания не меняют исходное отношение
+	if got := path.String(); got != "{(a, b), (b, c)}" {
+		t.Errorf("operand changed to %s", got)
+	}
+}
+
+// Каждое замыкание дает отношение с нужным свойством, а повторное замыкание ничего не добавляет
+func TestClosuresAreIdempotent(t *testing.T) {
+	r := build(t, "a b c d", "a-b", "b-c", "d-a", "c-c")
+	closures := []struct {
+		name     string
+		closure  func(*Relation) *Result
+		property func(*Relation) bool
+	}{
+		{"reflexive", ReflexiveClosure, (*Relation).IsReflexive},
+		{"symmetric", SymmetricClosure, (*Relation).IsSymmetric},
+		{"transitive", TransitiveClosure, (*Relation).IsTransitive},
+		{"equivalence", EquivalenceClosure, (*Relation).IsEquivalence},
+	}
+	for _, c := range closures {
+		closed := c.closure(r).Relation
+		if !c.property(closed) {
+			t.Errorf("%s closure %s lacks the property", c.name, closed)
+		}
+		for _, p := range r.Pairs() {
+			if !closed.Has(p.From, p.To) {
+				t.Errorf("%s closure lost pair %s", c.name, p)
+			}
+		}
+		if again := c.closure(closed).Relation; !again.Equal(closed) {
+			t.Errorf("%s closure of %s gave %s", c.name, closed, again)
+		}
+	}
+}
+
+func TestBinaryOperations(t *testing.T) {
+	r := build(t, "a b c", "a-b", "b-b")
+	s := build(t, "a b c", "b-c", "a-a")
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{OpComposition, "{(a, c), (b, c)}"},
+		{OpUnion, "{(a, a), (a, b), (b, b), (b, c)}"},
+		{OpIntersection, "{}"},
+	}
+	for _, tt := range tests {
+		res, err := Apply(tt.op, r, s, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.op, err)
+		}
+		if got := res.Relation.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.op, got, tt.want)
+		}
+	}
+
+	if _, err := Apply(OpUnion, r, nil, 0); err == nil {
+		t.Error("binary operation without a second relation: error = nil")
+	}
+	other := build(t, "a b", "a-b")
+	for _, op := range []Operation{OpComposition, OpUnion, OpIntersection} {
+		if _, err := Apply(op, r, other, 0); !errors.Is(err, ErrDifferentSets) {
+			t.Errorf("%s on different sets: error = %v, want %v", op, err, ErrDifferentSets)
+		}
+	}
+}
+
+func TestPower(t *testing.T) {
+	shift := build(t, "1 2 3 4", "1-2", "2-3", "3-4")
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "{(1, 1), (2, 2), (3, 3), (4, 4)}"},
+		{1, "{(1, 2), (2, 3), (3, 4)}"},
+		{2, "{(1, 3), (2, 4)}"},
+		{3, "{(1, 4)}"},
+		{4, "{}"},
+	}
+	for _, tt := range tests {
+		res, err := Power(shift, tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := res.Relation.String(); got != tt.want {
+			t.Errorf("R^%d = %s, want %s", tt.n, got, tt.want)
+		}
+		if len(res.Steps) != tt.n+1 {
+			t.Errorf("R^%d has %d steps, want %d", tt.n, len(res.Steps), tt.n+1)
+		}
+	}
+	for _, n := range []int{-1, MaxPower + 1} {
+		if _, err := Power(shift, n); err == nil {
+			t.Errorf("Power(%d): error = nil", n)
+		}
+	}
+}
+
+func TestParseOperation(t *testing.T) {
+	for _, op := range Operations {
+		if got, err := ParseOperation(string(op)); err != nil || got != op {
+			t.Errorf("ParseOperation(%q) = %q, %v", op, got, err)
+		}
+	}
+	if _, err := ParseOperation("closure"); err == nil {
+		t.Error("ParseOperation(unknown): error = nil")
+	}
+}
@@ -0,0 +1,204 @@
+package relation
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotPartialOrder = errors.New("relation is not a partial order")
+
+// Методы этого файла читают отношение как порядок: x ≤ y, если (x, y) ∈ R.
+// Осмысленные результаты они дают для частичного порядка, поэтому
+// Covering проверяет это явно, а остальные оставляют проверку вызывающему.
+
+// Covering строит отношение покрытия — ребра диаграммы Хассе: y покрывает x,
+// если x < y и нет элемента z, для которого x < z < y
+func (r *Relation) Covering() (*Relation, error) {
+	if !r.IsPartialOrder() {
+		return nil, ErrNotPartialOrder
+	}
+
+	n := r.set.Len()
+	cover := New(r.set)
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			if x == y || !r.matrix[x][y] {
+				continue
+			}
+			direct := true
+			for z := 0; z < n && direct; z++ {
+				if z != x && z != y && r.matrix[x][z] && r.matrix[z][y] {
+					direct = false
+				}
+			}
+			cover.matrix[x][y] = direct
+		}
+	}
+	return cover, nil
+}
+
+// MinimalElements возвращает элементы, меньше которых нет ни одного другого
+func (r *Relation) MinimalElements() []string {
+	return r.extremal(func(x, y int) bool { return r.matrix[y][x] })
+}
+
+// MaximalElements возвращает элементы, больше которых нет ни одного другого
+func (r *Relation) MaximalElements() []string {
+	return r.extremal(func(x, y int) bool { return r.matrix[x][y] })
+}
+
+// extremal оставляет элементы x, для которых beyond(x, y) не выполняется ни для какого y ≠ x
+func (r *Relation) extremal(beyond func(x, y int) bool) []string {
+	result := []string{}
+	for x := range r.matrix {
+		found := false
+		for y := range r.matrix {
+			if x != y && beyond(x, y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, r.set.Element(x))
+		}
+	}
+	return result
+}
+
+// Least возвращает наименьший элемент — тот, что меньше или равен всем остальным
+func (r *Relation) Least() (string, bool) {
+	return r.bound(r.allIndices(), true)
+}
+
+// Greatest возвращает наибольший элемент
+func (r *Relation) Greatest() (string, bool) {
+	return r.bound(r.allIndices(), false)
+}
+
+// UpperBounds возвращает верхние грани подмножества: элементы u, для которых s ≤ u при всех s из подмножества
+func (r *Relation) UpperBounds(subset []string) ([]string, error) {
+	indices, err := r.subsetIndices(subset)
+	if err != nil {
+		return nil, err
+	}
+	return r.elements(r.bounds(indices, true)), nil
+}
+
+// LowerBounds возвращает нижние грани подмножества
+func (r *Relation) LowerBounds(subset []string) ([]string, error) {
+	indices, err := r.subsetIndices(subset)
+	if err != nil {
+		return nil, err
+	}
+	return r.elements(r.bounds(indices, false)), nil
+}
+
+// Supremum возвращает точную верхнюю грань — наименьшую из верхних граней, если она есть
+func (r *Relation) Supremum(subset []string) (string, bool, error) {
+	indices, err := r.subsetIndices(subset)
+	if err != nil {
+		return "", false, err
+	}
+	sup, ok := r.bound(r.bounds(indices, true), true)
+	return sup, ok, nil
+}
+
+// Infimum возвращает точную нижнюю грань — наибольшую из нижних граней, если она есть
+func (r *Relation) Infimum(subset []string) (string, bool, error) {
+	indices, err := r.subsetIndices(subset)
+	if err != nil {
+		return "", false, err
+	}
+	inf, ok := r.bound(r.bounds(indices, false), false)
+	return inf, ok, nil
+}
+
+// LatticeViolation ищет пару элементов без точной верхней (missing = "supremum")
+// или нижней (missing = "infimum") грани. Если такой пары нет, found = false
+// и частичный порядок является решеткой.
+func (r *Relation) LatticeViolation() (pair Pair, missing string, found bool) {
+	n := r.set.Len()
+	for x := 0; x < n; x++ {
+		for y := x + 1; y < n; y++ {
+			if _, ok := r.bound(r.bounds([]int{x, y}, true), true); !ok {
+				return Pair{r.set.Element(x), r.set.Element(y)}, "supremum", true
+			}
+			if _, ok := r.bound(r.bounds([]int{x, y}, false), false); !ok {
+				return Pair{r.set.Element(x), r.set.Element(y)}, "infimum", true
+			}
+		}
+	}
+	return Pair{}, "", false
+}
+
+// IsLattice проверяет, что у любых двух элементов есть точные верхняя и нижняя грани
+func (r *Relation) IsLattice() bool {
+	if !r.IsPartialOrder() {
+		return false
+	}
+	_, _, found := r.LatticeViolation()
+	return !found
+}
+
+// bounds возвращает номера верхних (upper) или нижних граней элементов indices
+func (r *Relation) bounds(indices []int, upper bool) []int {
+	var result []int
+	for u := range r.matrix {
+		all := true
+		for _, s := range indices {
+			if upper && !r.matrix[s][u] || !upper && !r.matrix[u][s] {
+				all = false
+				break
+			}
+		}
+		if all {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// bound возвращает наименьший (least) или наибольший элемент среди candidates
+func (r *Relation) bound(candidates []int, least bool) (string, bool) {
+	for _, c := range candidates {
+		all := true
+		for _, o := range candidates {
+			if least && !r.matrix[c][o] || !least && !r.matrix[o][c] {
+				all = false
+				break
+			}
+		}
+		if all {
+			return r.set.Element(c), true
+		}
+	}
+	return "", false
+}
+
+func (r *Relation) allIndices() []int {
+	indices := make([]int, r.set.Len())
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+func (r *Relation) subsetIndices(subset []string) ([]int, error) {
+	indices := make([]int, 0, len(subset))
+	for _, e := range subset {
+		i, ok := r.set.Index(e)
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownElement, e)
+		}
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
+
+func (r *Relation) elements(indices []int) []string {
+	result := make([]string, len(indices))
+	for i, idx := range indices {
+		result[i] = r.set.Element(idx)
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+package relation
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// divisibility строит отношение «x делит y» на множестве чисел
+func divisibility(t *testing.T, numbers ...int) *Relation {
+	t.Helper()
+	elements := ""
+	var pairs []string
+	for _, x := range numbers {
+		elements += strconv.Itoa(x) + " "
+		for _, y := range numbers {
+			if y%x == 0 {
+				pairs = append(pairs, strconv.Itoa(x)+"-"+strconv.Itoa(y))
+			}
+		}
+	}
+	return build(t, elements, pairs...)
+}
+
+func TestCovering(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation *Relation
+		want     string
+	}{
+		{"divisors of 12", divisibility(t, 1, 2, 3, 4, 6, 12),
+			"{(1, 2), (1, 3), (2, 4), (2, 6), (3, 6), (4, 12), (6, 12)}"},
+		{"chain", build(t, "1 2 3", "1-1", "1-2", "1-3", "2-2", "2-3", "3-3"), "{(1, 2), (2, 3)}"},
+		{"antichain", build(t, "a b", "a-a", "b-b"), "{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cover, err := tt.relation.Covering()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cover.String(); got != tt.want {
+				t.Errorf("Covering() = %s, want %s", got, tt.want)
+			}
+			// Рефлексивно-транзитивное замыкание покрытия возвращает порядок
+			restored := TransitiveClosure(ReflexiveClosure(cover).Relation).Relation
+			if !restored.Equal(tt.relation) {
+				t.Errorf("closure of the covering = %s, want %s", restored, tt.relation)
+			}
+		})
+	}
+
+	if _, err := build(t, "a b", "a-b").Covering(); !errors.Is(err, ErrNotPartialOrder) {
+		t.Errorf("Covering of a non-order: error = %v, want %v", err, ErrNotPartialOrder)
+	}
+}
+
+func TestExtremalElements(t *testing.T) {
+	tests := []struct {
+		name             string
+		relation         *Relation
+		minimal, maximal []string
+		least, greatest  string // пустая строка — элемента нет
+	}{
+		{"divisors of 12", divisibility(t, 1, 2, 3, 4, 6, 12), []string{"1"}, []string{"12"}, "1", "12"},
+		{"2..6", divisibility(t, 2, 3, 4, 5, 6), []string{"2", "3", "5"}, []string{"4", "5", "6"}, "", ""},
+		{"with bottom", divisibility(t, 1, 2, 3), []string{"1"}, []string{"2", "3"}, "1", ""},
+		{"antichain", build(t, "a b", "a-a", "b-b"), []string{"a", "b"}, []string{"a", "b"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.relation.MinimalElements(); !slices.Equal(got, tt.minimal) {
+				t.Errorf("MinimalElements() = %v, want %v", got, tt.minimal)
+			}
+			if got := tt.relation.MaximalElements(); !slices.Equal(got, tt.maximal) {
+				t.Errorf("MaximalElements() = %v, want %v", got, tt.maximal)
+			}
+			if got, ok := tt.relation.Least(); got != tt.least || ok != (tt.least != "") {
+				t.Errorf("Least() = %q, %v, want %q", got, ok, tt.least)
+			}
+			if got, ok := tt.relation.Greatest(); got != tt.greatest || ok != (tt.greatest != "") {
+				t.Errorf("Greatest() = %q, %v, want %q", got, ok, tt.greatest)
+			}
+		})
+	}
+}
+
+func TestBounds(t *testing.T) {
+	r := divisibility(t, 1, 2, 3, 4, 6, 12)
+	tests := []struct {
+		subset       []string
+		upper, lower []string
+		sup, inf     string
+	}{
+		{[]string{"2", "3"}, []string{"6", "12"}, []string{"1"}, "6", "1"},
+		{[]string{"4", "6"}, []string{"12"}, []string{"1", "2"}, "12", "2"},
+		{[]string{"2", "4"}, []string{"4", "12"}, []string{"1", "2"}, "4", "2"},
+		// У пустого подмножества верхние грани — все элементы, супремум — наименьший
+		{[]string{}, []string{"1", "2", "3", "4", "6", "12"}, []string{"1", "2", "3", "4", "6", "12"}, "1", "12"},
+	}
+	for _, tt := range tests {
+		upper, err := r.UpperBounds(tt.subset)
+		if err != nil || !slices.Equal(upper, tt.upper) {
+			t.Errorf("UpperBounds(%v) = %v, %v, want %v", tt.subset, upper, err, tt.upper)
+		}
+		lower, err := r.LowerBounds(tt.subset)
+		if err != nil || !slices.Equal(lower, tt.lower) {
+			t.Errorf("LowerBounds(%v) = %v, %v, want %v", tt.subset, lower, err, tt.lower)
+		}
+		if sup, ok, err := r.Supremum(tt.subset); err != nil || !ok || sup != tt.sup {
+			t.Errorf("Supremum(%v) = %q, %v, %v, want %q", tt.subset, sup, ok, err, tt.sup)
+		}
+		if inf, ok, err := r.Infimum(tt.subset); err != nil || !ok || inf != tt.inf {
+			t.Errorf("Infimum(%v) = %q, %v, %v, want %q", tt.subset, inf, ok, err, tt.inf)
+		}
+	}
+
+	// У 4 и 6 на {2, 4, 6, 24, 36} две несравнимые верхние грани — супремума нет
+	noSup := divisibility(t, 2, 4, 6, 24, 36)
+	if upper, _ := noSup.UpperBounds([]string{"4", "6"}); !slices.Equal(upper, []string{"24", "36"}) {
+		t.Errorf("UpperBounds(4, 6) = %v, want [24 36]", upper)
+	}
+	if sup, ok, _ := noSup.Supremum([]string{"4", "6"}); ok {
+		t.Errorf("Supremum(4, 6) = %q, want none", sup)
+	}
+	if _, err := r.UpperBounds([]string{"5"}); !errors.Is(err, ErrUnknownElement) {
+		t.Errorf("UpperBounds with unknown element: error = %v, want %v", err, ErrUnknownElement)
+	}
+}
+
+func TestLattice(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation *Relation
+		lattice  bool
+		pair     Pair
+		missing  string
+	}{
+		{"divisors of 12", divisibility(t, 1, 2, 3, 4, 6, 12), true, Pair{}, ""},
+		{"chain", build(t, "1 2", "1-1", "1-2", "2-2"), true, Pair{}, ""},
+		{"no top", divisibility(t, 1, 2, 3), false, Pair{"2", "3"}, "supremum"},
+		{"no bottom", divisibility(t, 2, 3, 6), false, Pair{"2", "3"}, "infimum"},
+		{"two middle bounds", divisibility(t, 1, 2, 3, 12, 18, 36), false, Pair{"2", "3"}, "supremum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.relation.IsLattice(); got != tt.lattice {
+				t.Errorf("IsLattice() = %v, want %v", got, tt.lattice)
+			}
+			pair, missing, found := tt.relation.LatticeViolation()
+			if found == tt.lattice || pair != tt.pair || missing != tt.missing {
+				t.Errorf("LatticeViolation() = %v, %q, %v, want %v, %q", pair, missing, found, tt.pair, tt.missing)
+			}
+		})
+	}
+
+	if build(t, "a b", "a-b").IsLattice() {
+		t.Error("IsLattice() of a non-order = true")
+	}
+}
@@ -0,0 +1,143 @@
+package relation
+
+import "fmt"
+
+// Property — свойство бинарного отношения
+type Property string
+
+const (
+	Reflexive     Property = "reflexive"     // aRa для всех a
+	Irreflexive   Property = "irreflexive"   // ни для какого a не aRa
+	Symmetric     Property = "symmetric"     // aRb ⇒ bRa
+	Antisymmetric Property = "antisymmetric" // aRb и bRa ⇒ a = b
+	Asymmetric    Property = "asymmetric"    // aRb ⇒ не bRa
+	Transitive    Property = "transitive"    // aRb и bRc ⇒ aRc
+	Connex        Property = "connex"        // aRb или bRa для любых a, b (полнота)
+)
+
+// Properties — все свойства в порядке вывода
+var Properties = []Property{Reflexive, Irreflexive, Symmetric, Antisymmetric, Asymmetric, Transitive, Connex}
+
+// ParseProperty проверяет название свойства; total принимается как синоним connex
+func ParseProperty(s string) (Property, error) {
+	if s == "total" {
+		return Connex, nil
+	}
+	for _, p := range Properties {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown relation property %q", s)
+}
+
+// Satisfies проверяет свойство отношения
+func (r *Relation) Satisfies(p Property) bool {
+	switch p {
+	case Reflexive:
+		return r.IsReflexive()
+	case Irreflexive:
+		return r.IsIrreflexive()
+	case Symmetric:
+		return r.IsSymmetric()
+	case Antisymmetric:
+		return r.IsAntisymmetric()
+	case Asymmetric:
+		return r.IsAsymmetric()
+	case Transitive:
+		return r.IsTransitive()
+	case Connex:
+		return r.IsConnex()
+	default:
+		return false
+	}
+}
+
+// PropertySet возвращает результат проверки каждого свойства
+func (r *Relation) PropertySet() map[Property]bool {
+	result := make(map[Property]bool, len(Properties))
+	for _, p := range Properties {
+		result[p] = r.Satisfies(p)
+	}
+	return result
+}
+
+func (r *Relation) IsReflexive() bool {
+	for i := range r.matrix {
+		if !r.matrix[i][i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Relation) IsIrreflexive() bool {
+	for i := range r.matrix {
+		if r.matrix[i][i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Relation) IsSymmetric() bool {
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if v && !r.matrix[j][i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *Relation) IsAntisymmetric() bool {
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if i != j && v && r.matrix[j][i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsAsymmetric — асимметричность; она равносильна антисимметричности вместе с иррефлексивностью
+func (r *Relation) IsAsymmetric() bool {
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if v && r.matrix[j][i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *Relation) IsTransitive() bool {
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if !v {
+				continue
+			}
+			for k, w := range r.matrix[j] {
+				if w && !r.matrix[i][k] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// IsConnex — полнота: любые два элемента (в том числе a с самим собой) сравнимы
+func (r *Relation) IsConnex() bool {
+	for i, row := range r.matrix {
+		for j := i; j < len(row); j++ {
+			if !row[j] && !r.matrix[j][i] {
+				return false
+			}
+		}
+	}
+	return true
+}
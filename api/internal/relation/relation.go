@@ -0,0 +1,170 @@
+package relation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Pair — упорядоченная пара (From, To), то есть From R To
+type Pair struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (p Pair) String() string {
+	return "(" + p.From + ", " + p.To + ")"
+}
+
+// Relation — бинарное отношение на конечном множестве, хранится матрицей смежности
+type Relation struct {
+	set    *Set
+	matrix [][]bool
+}
+
+// New создает пустое отношение на множестве
+func New(set *Set) *Relation {
+	matrix := make([][]bool, set.Len())
+	for i := range matrix {
+		matrix[i] = make([]bool, set.Len())
+	}
+	return &Relation{set: set, matrix: matrix}
+}
+
+// FromPairs создает отношение из пар; элементы пар должны принадлежать множеству
+func FromPairs(set *Set, pairs []Pair) (*Relation, error) {
+	r := New(set)
+	for _, p := range pairs {
+		if err := r.Add(p.From, p.To); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// FromMatrix создает отношение из квадратной матрицы смежности размера |set|
+func FromMatrix(set *Set, matrix [][]bool) (*Relation, error) {
+	if len(matrix) != set.Len() {
+		return nil, fmt.Errorf("matrix must have %d rows, got %d", set.Len(), len(matrix))
+	}
+	r := New(set)
+	for i, row := range matrix {
+		if len(row) != set.Len() {
+			return nil, fmt.Errorf("matrix row %d must have %d columns, got %d", i+1, set.Len(), len(row))
+		}
+		copy(r.matrix[i], row)
+	}
+	return r, nil
+}
+
+func (r *Relation) Set() *Set {
+	return r.set
+}
+
+// Add добавляет пару (a, b)
+func (r *Relation) Add(a, b string) error {
+	i, j, err := r.indices(a, b)
+	if err != nil {
+		return err
+	}
+	r.matrix[i][j] = true
+	return nil
+}
+
+// Remove удаляет пару (a, b)
+func (r *Relation) Remove(a, b string) error {
+	i, j, err := r.indices(a, b)
+	if err != nil {
+		return err
+	}
+	r.matrix[i][j] = false
+	return nil
+}
+
+// Has сообщает, находится ли a в отношении с b; для чужих элементов возвращает false
+func (r *Relation) Has(a, b string) bool {
+	i, j, err := r.indices(a, b)
+	return err == nil && r.matrix[i][j]
+}
+
+func (r *Relation) indices(a, b string) (int, int, error) {
+	i, ok := r.set.Index(a)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w %q", ErrUnknownElement, a)
+	}
+	j, ok := r.set.Index(b)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w %q", ErrUnknownElement, b)
+	}
+	return i, j, nil
+}
+
+// Len возвращает количество пар
+func (r *Relation) Len() int {
+	n := 0
+	for _, row := range r.matrix {
+		for _, v := range row {
+			if v {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Pairs возвращает пары в порядке элементов множества (по строкам матрицы)
+func (r *Relation) Pairs() []Pair {
+	pairs := make([]Pair, 0, r.Len())
+	for i, row := range r.matrix {
+		for j, v := range row {
+			if v {
+				pairs = append(pairs, Pair{From: r.set.Element(i), To: r.set.Element(j)})
+			}
+		}
+	}
+	return pairs
+}
+
+// Matrix возвращает копию матрицы смежности
+func (r *Relation) Matrix() [][]bool {
+	matrix := make([][]bool, len(r.matrix))
+	for i, row := range r.matrix {
+		matrix[i] = append([]bool(nil), row...)
+	}
+	return matrix
+}
+
+func (r *Relation) Clone() *Relation {
+	return &Relation{set: r.set, matrix: r.Matrix()}
+}
+
+// Equal сравнивает отношения как множества пар на равных множествах
+func (r *Relation) Equal(o *Relation) bool {
+	if !r.set.Equal(o.set) || r.Len() != o.Len() {
+		return false
+	}
+	for _, p := range r.Pairs() {
+		if !o.Has(p.From, p.To) {
+			return false
+		}
+	}
+	return true
+}
+
+// String возвращает запись вида {(a, b), (b, c)}
+func (r *Relation) String() string {
+	pairs := r.Pairs()
+	parts := make([]string, len(pairs))
+	for i, p := range pairs {
+		parts[i] = p.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// MarshalJSON выводит отношение как {"elements": [...], "pairs": [{"from", "to"}, ...]}
+func (r *Relation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Elements []string `json:"elements"`
+		Pairs    []Pair   `json:"pairs"`
+	}{r.set.Elements(), r.Pairs()})
+}
@@ -0,0 +1,120 @@
+package relation
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// build создает отношение на множестве из элементов через пробел;
+// пары записываются как "a-b"
+func build(t *testing.T, elements string, pairs ...string) *Relation {
+	t.Helper()
+	set, err := NewSet(strings.Fields(elements)...)
+	if err != nil {
+		t.Fatalf("NewSet(%q): %v", elements, err)
+	}
+	r := New(set)
+	for _, p := range pairs {
+		from, to, _ := strings.Cut(p, "-")
+		if err := r.Add(from, to); err != nil {
+			t.Fatalf("Add(%q): %v", p, err)
+		}
+	}
+	return r
+}
+
+func TestNewSet(t *testing.T) {
+	if _, err := NewSet("a", "b", "a"); !errors.Is(err, ErrDuplicateElement) {
+		t.Errorf("duplicate element: error = %v, want %v", err, ErrDuplicateElement)
+	}
+	if _, err := NewSet("a", ""); !errors.Is(err, ErrEmptyElement) {
+		t.Errorf("empty element: error = %v, want %v", err, ErrEmptyElement)
+	}
+	r := build(t, "a b")
+	if err := r.Add("a", "c"); !errors.Is(err, ErrUnknownElement) {
+		t.Errorf("Add with unknown element: error = %v, want %v", err, ErrUnknownElement)
+	}
+	if r.Has("a", "c") {
+		t.Error("Has with unknown element = true")
+	}
+}
+
+func TestFromMatrix(t *testing.T) {
+	set, _ := NewSet("a", "b")
+	r, err := FromMatrix(set, [][]bool{{false, true}, {true, true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.String(), "{(a, b), (b, a), (b, b)}"; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if _, err := FromMatrix(set, [][]bool{{true}}); err == nil {
+		t.Error("FromMatrix with wrong row count: error = nil")
+	}
+	if _, err := FromMatrix(set, [][]bool{{true}, {true, false}}); err == nil {
+		t.Error("FromMatrix with short row: error = nil")
+	}
+}
+
+func TestProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation *Relation
+		holds    []Property
+	}{
+		{"empty", build(t, "a b"),
+			[]Property{Irreflexive, Symmetric, Antisymmetric, Asymmetric, Transitive}},
+		{"identity", build(t, "a b", "a-a", "b-b"),
+			[]Property{Reflexive, Symmetric, Antisymmetric, Transitive}},
+		{"full", build(t, "a b", "a-a", "a-b", "b-a", "b-b"),
+			[]Property{Reflexive, Symmetric, Transitive, Connex}},
+		{"less or equal", build(t, "1 2 3", "1-1", "1-2", "1-3", "2-2", "2-3", "3-3"),
+			[]Property{Reflexive, Antisymmetric, Transitive, Connex}},
+		{"less", build(t, "1 2 3", "1-2", "1-3", "2-3"),
+			[]Property{Irreflexive, Antisymmetric, Asymmetric, Transitive}},
+		{"path", build(t, "a b c", "a-b", "b-c"),
+			[]Property{Irreflexive, Antisymmetric, Asymmetric}},
+		{"two-cycle", build(t, "a b", "a-b", "b-a"),
+			[]Property{Irreflexive, Symmetric}},
+		{"loop only", build(t, "a b", "a-a"),
+			[]Property{Symmetric, Antisymmetric, Transitive}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range Properties {
+				want := slices.Contains(tt.holds, p)
+				if got := tt.relation.Satisfies(p); got != want {
+					t.Errorf("Satisfies(%s) = %v, want %v", p, got, want)
+				}
+				if got := tt.relation.PropertySet()[p]; got != want {
+					t.Errorf("PropertySet()[%s] = %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation *Relation
+		want     []Class
+	}{
+		{"identity", build(t, "a b", "a-a", "b-b"), []Class{Equivalence, PartialOrder, Function}},
+		{"full", build(t, "a b", "a-a", "a-b", "b-a", "b-b"), []Class{Equivalence}},
+		{"less or equal", build(t, "1 2 3", "1-1", "1-2", "1-3", "2-2", "2-3", "3-3"), []Class{PartialOrder, LinearOrder}},
+		{"less", build(t, "1 2 3", "1-2", "1-3", "2-3"), []Class{StrictOrder}},
+		{"function", build(t, "a b c", "a-b", "b-c", "c-c"), []Class{Function}},
+		{"divisibility", build(t, "1 2 3", "1-1", "1-2", "1-3", "2-2", "3-3"), []Class{PartialOrder}},
+		{"nothing", build(t, "a b", "a-b", "b-a", "a-a"), []Class{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.relation.Classify(); !slices.Equal(got, tt.want) {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
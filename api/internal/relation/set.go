@@ -0,0 +1,86 @@
+// Пакет relation моделирует конечное множество и бинарное отношение на нем:
+// проверка свойств отношения и его классификация. Это основа для автоматической
+// проверки вопросов об отношениях и упражнений тренажера на сервере.
+package relation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrDuplicateElement = errors.New("duplicate element")
+	ErrEmptyElement     = errors.New("empty element name")
+	ErrUnknownElement   = errors.New("unknown element")
+)
+
+// Set — конечное множество с фиксированным порядком элементов.
+// Порядок задает строки и столбцы матрицы отношения и порядок вывода пар.
+type Set struct {
+	elements []string
+	index    map[string]int
+}
+
+// NewSet создает множество из элементов в заданном порядке
+func NewSet(elements ...string) (*Set, error) {
+	s := &Set{
+		elements: make([]string, 0, len(elements)),
+		index:    make(map[string]int, len(elements)),
+	}
+	for _, e := range elements {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			return nil, ErrEmptyElement
+		}
+		if _, ok := s.index[e]; ok {
+			return nil, fmt.Errorf("%w %q", ErrDuplicateElement, e)
+		}
+		s.index[e] = len(s.elements)
+		s.elements = append(s.elements, e)
+	}
+	return s, nil
+}
+
+func (s *Set) Len() int {
+	return len(s.elements)
+}
+
+// Elements возвращает копию списка элементов
+func (s *Set) Elements() []string {
+	return append([]string(nil), s.elements...)
+}
+
+// Element возвращает элемент по номеру
+func (s *Set) Element(i int) string {
+	return s.elements[i]
+}
+
+// Index возвращает номер элемента в множестве
+func (s *Set) Index(e string) (int, bool) {
+	i, ok := s.index[e]
+	return i, ok
+}
+
+func (s *Set) Contains(e string) bool {
+	_, ok := s.index[e]
+	return ok
+}
+
+// Equal сравнивает множества без учета порядка элементов
+func (s *Set) Equal(o *Set) bool {
+	if s.Len() != o.Len() {
+		return false
+	}
+	for _, e := range s.elements {
+		if !o.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// String возвращает запись вида {a, b, c}
+func (s *Set) String() string {
+	return "{" + strings.Join(s.elements, ", ") + "}"
+}
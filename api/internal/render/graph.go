@@ -0,0 +1,83 @@
+package render
+
+import (
+	"api/internal/relation"
+	"math"
+)
+
+// Graph рисует отношение ориентированным графом: вершины по окружности в порядке
+// множества, как на холсте тренажера, пары — стрелками, пары (x, x) — петлями
+// снаружи окружности. Встречные пары рисуются двумя дугами, чтобы не сливаться.
+func Graph(r *relation.Relation) []byte {
+	elements := r.Set().Elements()
+	matrix := r.Matrix()
+	n := len(elements)
+
+	radius := 0.0
+	if n > 1 {
+		// Соседние вершины не ближе трех радиусов вершины
+		radius = math.Max(60, 3*nodeRadius/(2*math.Sin(math.Pi/float64(n))))
+	}
+	// Место под петли снаружи окружности
+	size := 2 * (radius + 2*nodeRadius + 24 + margin)
+	center := size / 2
+
+	positions := make([][2]float64, n)
+	for i := range positions {
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+		positions[i] = [2]float64{center + radius*math.Cos(angle), center + radius*math.Sin(angle)}
+	}
+
+	w := newSVG(size, size)
+	w.printf(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">` +
+		`<path d="M0,0 L10,5 L0,10 z" fill="#212529"/></marker></defs>`)
+
+	for i := range matrix {
+		for j, v := range matrix[i] {
+			if !v {
+				continue
+			}
+			if i == j {
+				drawLoop(w, positions[i], center)
+			} else {
+				drawArrow(w, positions[i], positions[j], matrix[j][i])
+			}
+		}
+	}
+	for i, e := range elements {
+		w.node(positions[i][0], positions[i][1], e)
+	}
+	return w.bytes()
+}
+
+// drawArrow рисует стрелку между границами вершин; при встречной паре —
+// дугой, отогнутой вправо по направлению стрелки
+func drawArrow(w *svgWriter, from, to [2]float64, curved bool) {
+	dx, dy := to[0]-from[0], to[1]-from[1]
+	length := math.Hypot(dx, dy)
+	ux, uy := dx/length, dy/length
+
+	x1, y1 := from[0]+ux*nodeRadius, from[1]+uy*nodeRadius
+	x2, y2 := to[0]-ux*nodeRadius, to[1]-uy*nodeRadius
+	if !curved {
+		w.printf(`<line class="edge" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" marker-end="url(#arrow)"/>`, x1, y1, x2, y2)
+		return
+	}
+
+	// Контрольная точка смещена перпендикулярно отрезку
+	const bend = 20
+	cx, cy := (x1+x2)/2-uy*bend, (y1+y2)/2+ux*bend
+	w.printf(`<path class="edge" d="M%.1f,%.1f Q%.1f,%.1f %.1f,%.1f" marker-end="url(#arrow)"/>`, x1, y1, cx, cy, x2, y2)
+}
+
+// drawLoop рисует петлю дугой на внешней стороне вершины
+func drawLoop(w *svgWriter, at [2]float64, center float64) {
+	angle := math.Atan2(at[1]-center, at[0]-center)
+	if at[0] == center && at[1] == center {
+		angle = -math.Pi / 2
+	}
+	const spread = 0.5
+	x1, y1 := at[0]+nodeRadius*math.Cos(angle-spread), at[1]+nodeRadius*math.Sin(angle-spread)
+	x2, y2 := at[0]+nodeRadius*math.Cos(angle+spread), at[1]+nodeRadius*math.Sin(angle+spread)
+	w.printf(`<path class="edge" d="M%.1f,%.1f A12,12 0 1,1 %.1f,%.1f" marker-end="url(#arrow)"/>`, x1, y1, x2, y2)
+}
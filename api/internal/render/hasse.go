@@ -0,0 +1,95 @@
+package render
+
+import (
+	"api/internal/relation"
+	"sort"
+)
+
+const (
+	layerHeight = 80
+	nodeSpacing = 70
+)
+
+// Hasse рисует диаграмму Хассе частичного порядка. Элемент стоит на уровне,
+// равном длине самой длинной цепочки покрытий, ведущей к нему снизу, так что
+// каждое ребро идет вверх. Внутри уровня элементы упорядочены по среднему
+// положению нижних соседей, чтобы ребра меньше пересекались.
+func Hasse(r *relation.Relation) ([]byte, error) {
+	cover, err := r.Covering()
+	if err != nil {
+		return nil, err
+	}
+	elements := r.Set().Elements()
+	edges := cover.Matrix()
+	n := len(elements)
+
+	// Уровни: релаксация по ребрам покрытия, цепочка не длиннее n
+	level := make([]int, n)
+	for pass := 0; pass < n; pass++ {
+		for x := range edges {
+			for y, v := range edges[x] {
+				if v && level[y] < level[x]+1 {
+					level[y] = level[x] + 1
+				}
+			}
+		}
+	}
+
+	height := 0
+	for _, l := range level {
+		height = max(height, l+1)
+	}
+	layers := make([][]int, height)
+	for i, l := range level {
+		layers[l] = append(layers[l], i)
+	}
+
+	widest := 0
+	for _, layer := range layers {
+		widest = max(widest, len(layer))
+	}
+	width := float64(max(widest-1, 0))*nodeSpacing + 2*(nodeRadius+margin)
+	total := float64(max(height-1, 0))*layerHeight + 2*(nodeRadius+margin)
+
+	positions := make([][2]float64, n)
+	for l, layer := range layers {
+		if l > 0 {
+			sort.SliceStable(layer, func(a, b int) bool {
+				return barycenter(edges, positions, layer[a]) < barycenter(edges, positions, layer[b])
+			})
+		}
+		offset := (width - float64(len(layer)-1)*nodeSpacing) / 2
+		for k, i := range layer {
+			positions[i] = [2]float64{offset + float64(k)*nodeSpacing, total - nodeRadius - margin - float64(l)*layerHeight}
+		}
+	}
+
+	w := newSVG(width, total)
+	for x := range edges {
+		for y, v := range edges[x] {
+			if v {
+				w.printf(`<line class="edge" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`,
+					positions[x][0], positions[x][1], positions[y][0], positions[y][1])
+			}
+		}
+	}
+	for i, e := range elements {
+		w.node(positions[i][0], positions[i][1], e)
+	}
+	return w.bytes(), nil
+}
+
+// barycenter — среднее горизонтальное положение элементов, которые покрывает y
+func barycenter(edges [][]bool, positions [][2]float64, y int) float64 {
+	sum, count := 0.0, 0
+	for x := range edges {
+		if edges[x][y] {
+			sum += positions[x][0]
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
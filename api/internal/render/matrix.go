@@ -0,0 +1,38 @@
+package render
+
+import "api/internal/relation"
+
+const cellSize = 32
+
+// Matrix рисует матрицу отношения: строки и столбцы подписаны элементами,
+// клетки пар отношения закрашены и содержат 1, остальные — 0
+func Matrix(r *relation.Relation) []byte {
+	elements := r.Set().Elements()
+	matrix := r.Matrix()
+
+	size := float64(len(elements)+1)*cellSize + 2*margin
+	w := newSVG(size, size)
+
+	cell := func(i, j int) (float64, float64) {
+		return margin + float64(j+1)*cellSize, margin + float64(i+1)*cellSize
+	}
+	for k, e := range elements {
+		x, _ := cell(0, k)
+		w.text(x+cellSize/2, margin+cellSize/2, e, "label header")
+		_, y := cell(k, 0)
+		w.text(margin+cellSize/2, y+cellSize/2, e, "label header")
+	}
+
+	for i, row := range matrix {
+		for j, v := range row {
+			x, y := cell(i, j)
+			class, value := "cell", "0"
+			if v {
+				class, value = "cell-on", "1"
+			}
+			w.printf(`<rect class="%s" x="%.1f" y="%.1f" width="%d" height="%d"/>`, class, x, y, cellSize, cellSize)
+			w.text(x+cellSize/2, y+cellSize/2, value, "label")
+		}
+	}
+	return w.bytes()
+}
@@ -0,0 +1,150 @@
+// Пакет render рисует отношения в SVG: граф с петлями, матрицу из нулей
+// и единиц и диаграмму Хассе частичного порядка. Картинки статичны, поэтому
+// их можно вставлять в вопросы, разбор попыток и выгрузку в PDF.
+package render
+
+import (
+	"api/internal/relation"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+)
+
+// View — вид изображения отношения
+type View string
+
+const (
+	ViewGraph  View = "graph"
+	ViewMatrix View = "matrix"
+	ViewHasse  View = "hasse"
+)
+
+func ParseView(s string) (View, error) {
+	switch View(s) {
+	case ViewGraph, ViewMatrix, ViewHasse:
+		return View(s), nil
+	}
+	return "", fmt.Errorf("unknown relation view %q", s)
+}
+
+// Render рисует отношение в заданном виде
+func Render(view View, r *relation.Relation) ([]byte, error) {
+	switch view {
+	case ViewGraph:
+		return Graph(r), nil
+	case ViewMatrix:
+		return Matrix(r), nil
+	case ViewHasse:
+		return Hasse(r)
+	default:
+		return nil, fmt.Errorf("unknown relation view %q", view)
+	}
+}
+
+// Key возвращает ключ изображения по каноническому виду отношения:
+// элементы в порядке множества и матрица построчно. Одинаковые отношения
+// дают одинаковый ключ, поэтому он годится и для кэша, и для ETag.
+func Key(view View, r *relation.Relation) string {
+	var b strings.Builder
+	b.WriteString(string(view))
+	for _, e := range r.Set().Elements() {
+		b.WriteByte(0)
+		b.WriteString(e)
+	}
+	b.WriteByte(0)
+	for _, row := range r.Matrix() {
+		for _, v := range row {
+			if v {
+				b.WriteByte('1')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Cache хранит готовые изображения по ключу Key. При переполнении
+// вытесняются самые старые записи.
+type Cache struct {
+	mu    sync.Mutex
+	limit int
+	items map[string][]byte
+	order []string
+}
+
+func NewCache(limit int) *Cache {
+	return &Cache{limit: limit, items: make(map[string][]byte, limit)}
+}
+
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	svg, ok := c.items[key]
+	return svg, ok
+}
+
+func (c *Cache) Put(key string, svg []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; ok {
+		return
+	}
+	if len(c.order) >= c.limit {
+		delete(c.items, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.items[key] = svg
+	c.order = append(c.order, key)
+}
+
+// Общие размеры и стили
+const (
+	nodeRadius = 18
+	margin     = 24
+)
+
+const style = `<style>` +
+	`.node{fill:#fff;stroke:#212529;stroke-width:1.5}` +
+	`.edge{fill:none;stroke:#212529;stroke-width:1.5}` +
+	`.label{font-family:Inter,Arial,sans-serif;font-size:14px;text-anchor:middle;dominant-baseline:central;fill:#212529}` +
+	`.cell{fill:#fff;stroke:#adb5bd}` +
+	`.cell-on{fill:#cfe2ff;stroke:#adb5bd}` +
+	`.header{font-weight:600}` +
+	`</style>`
+
+// svgWriter собирает документ SVG
+type svgWriter struct {
+	buf bytes.Buffer
+}
+
+func newSVG(width, height float64) *svgWriter {
+	w := &svgWriter{}
+	fmt.Fprintf(&w.buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`,
+		width, height, width, height)
+	w.buf.WriteString(style)
+	return w
+}
+
+func (w *svgWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+func (w *svgWriter) node(x, y float64, label string) {
+	w.printf(`<circle class="node" cx="%.1f" cy="%.1f" r="%d"/>`, x, y, nodeRadius)
+	w.text(x, y, label, "label")
+}
+
+func (w *svgWriter) text(x, y float64, label, class string) {
+	w.printf(`<text class="%s" x="%.1f" y="%.1f">%s</text>`, class, x, y, html.EscapeString(label))
+}
+
+func (w *svgWriter) bytes() []byte {
+	w.buf.WriteString(`</svg>`)
+	return w.buf.Bytes()
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"api/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const bankColumns = `id, course_id, owner_id, title, shared, created_at`
+
+func scanBank(row interface{ Scan(...any) error }, bank *models.QuestionBank) error {
+	return row.Scan(&bank.ID, &bank.CourseID, &bank.OwnerID, &bank.Title, &bank.Shared, &bank.CreatedAt)
+}
+
+func (r *TestRepository) CreateBank(ctx context.Context, bank *models.QuestionBank) error {
+	query := `INSERT INTO question_banks (course_id, owner_id, title, shared)
+              VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+
+	return r.Db.QueryRowContext(ctx, query,
+		bank.CourseID, bank.OwnerID, bank.Title, bank.Shared,
+	).Scan(&bank.ID, &bank.CreatedAt)
+}
+
+func (r *TestRepository) UpdateBank(ctx context.Context, bank *models.QuestionBank) error {
+	query := `UPDATE question_banks SET title = $1, shared = $2 WHERE id = $3`
+
+	_, err := r.Db.ExecContext(ctx, query, bank.Title, bank.Shared, bank.ID)
+	return err
+}
+
+func (r *TestRepository) DeleteBank(ctx context.Context, bankID int) error {
+	query := `DELETE FROM question_banks WHERE id = $1`
+
+	_, err := r.Db.ExecContext(ctx, query, bankID)
+	return err
+}
+
+func (r *TestRepository) GetBank(ctx context.Context, bankID int) (*models.QuestionBank, error) {
+	query := `SELECT ` + bankColumns + ` FROM question_banks WHERE id = $1`
+
+	var bank models.QuestionBank
+	if err := scanBank(r.Db.QueryRowContext(ctx, query, bankID), &bank); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("question bank %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &bank, nil
+}
+
+// GetCourseBanks возвращает банки курса, доступные преподавателю: свои и общие
+func (r *TestRepository) GetCourseBanks(ctx context.Context, courseID, teacherID int) ([]models.QuestionBank, error) {
+	query := `SELECT ` + bankColumns + ` FROM question_banks
+              WHERE course_id = $1 AND (owner_id = $2 OR shared)
+              ORDER BY title, id`
+
+	rows, err := r.Db.QueryContext(ctx, query, courseID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	banks := []models.QuestionBank{}
+	for rows.Next() {
+		var bank models.QuestionBank
+		if err := scanBank(rows, &bank); err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+
+	return banks, rows.Err()
+}
+
+// Вопрос банка используется тестами через test_bank_questions, а попытками —
+// через attempt_questions и ответы; ссылки попыток остаются и после новой версии теста
+const bankQuestionColumns = `q.id, q.question_text, q.question_type, q.points, q.position,
+              q.scoring_mode, q.bank_id, q.tags, q.difficulty,
+              (SELECT COUNT(*) FROM test_bank_questions tb WHERE tb.question_id = q.id),
+              (SELECT COUNT(*) FROM (
+                  SELECT aq.attempt_id FROM attempt_questions aq WHERE aq.question_id = q.id
+                  UNION
+                  SELECT ua.attempt_id FROM user_answers ua WHERE ua.question_id = q.id
+              ) used)`
+
+func scanBankQuestion(row interface{ Scan(...any) error }, q *models.BankQuestion) error {
+	var bankID int
+	err := row.Scan(&q.ID, &q.QuestionText, &q.QuestionType, &q.Points, &q.Position,
+		&q.ScoringMode, &bankID, pq.Array(&q.Tags), &q.Difficulty, &q.UsedIn, &q.AttemptUses)
+	if err != nil {
+		return err
+	}
+
+	q.BankID = &bankID
+	if q.Tags == nil {
+		q.Tags = []string{}
+	}
+	return nil
+}
+
+// GetBankQuestions возвращает вопросы банка с учетом фильтра по тегу и сложности
+func (r *TestRepository) GetBankQuestions(ctx context.Context, bankID int, filter models.BankQuestionFilter) ([]models.BankQuestion, error) {
+	query := `SELECT ` + bankQuestionColumns + `
+              FROM questions q
+              WHERE q.bank_id = $1 AND NOT q.archived
+                AND ($2 = '' OR $2 = ANY(q.tags))
+                AND ($3 = 0 OR q.difficulty = $3)
+              ORDER BY q.position, q.id`
+
+	rows, err := r.Db.QueryContext(ctx, query, bankID, filter.Tag, filter.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []models.BankQuestion{}
+	for rows.Next() {
+		var q models.BankQuestion
+		if err := scanBankQuestion(rows, &q); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+
+	return questions, rows.Err()
+}
+
+func (r *TestRepository) GetBankQuestion(ctx context.Context, questionID int) (*models.BankQuestion, error) {
+	query := `SELECT ` + bankQuestionColumns + `
+              FROM questions q WHERE q.id = $1 AND q.bank_id IS NOT NULL AND NOT q.archived`
+
+	var q models.BankQuestion
+	if err := scanBankQuestion(r.Db.QueryRowContext(ctx, query, questionID), &q); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("bank question %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+// CountBankUses возвращает количество ссылок тестов на вопросы банка
+func (r *TestRepository) CountBankUses(ctx context.Context, bankID int) (int, error) {
+	query := `SELECT COUNT(*) FROM test_bank_questions tb
+              JOIN questions q ON q.id = tb.question_id
+              WHERE q.bank_id = $1`
+
+	var count int
+	err := r.Db.QueryRowContext(ctx, query, bankID).Scan(&count)
+	return count, err
+}
+
+// CountBankAttempts возвращает количество попыток, в которые попадали вопросы
+// банка, включая архивные
+func (r *TestRepository) CountBankAttempts(ctx context.Context, bankID int) (int, error) {
+	query := `SELECT COUNT(*) FROM (
+                  SELECT aq.attempt_id FROM attempt_questions aq
+                  JOIN questions q ON q.id = aq.question_id WHERE q.bank_id = $1
+                  UNION
+                  SELECT ua.attempt_id FROM user_answers ua
+                  JOIN questions q ON q.id = ua.question_id WHERE q.bank_id = $1
+              ) used`
+
+	var count int
+	err := r.Db.QueryRowContext(ctx, query, bankID).Scan(&count)
+	return count, err
+}
+
+// ArchiveBankQuestion скрывает вопрос из банка, сохраняя его для попыток, в которые он попадал
+func (r *TestRepository) ArchiveBankQuestion(ctx context.Context, questionID int) error {
+	query := `UPDATE questions SET archived = TRUE WHERE id = $1 AND bank_id IS NOT NULL`
+
+	_, err := r.Db.ExecContext(ctx, query, questionID)
+	return err
+}
+
+func (r *TestRepository) DeleteBankQuestion(ctx context.Context, questionID int) error {
+	query := `DELETE FROM questions WHERE id = $1 AND bank_id IS NOT NULL`
+
+	_, err := r.Db.ExecContext(ctx, query, questionID)
+	return err
+}
+
+func (r *txRepository) CreateBankQuestion(ctx context.Context, q *models.BankQuestion) error {
+	query := `INSERT INTO questions (bank_id, question_text, question_type, points, position,
+                                     scoring_mode, tags, difficulty)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+
+	return r.tx.QueryRowContext(ctx, query,
+		q.BankID, q.QuestionText, q.QuestionType, q.Points, q.Position,
+		q.ScoringMode, pq.Array(q.Tags), q.Difficulty,
+	).Scan(&q.ID)
+}
+
+func (r *txRepository) UpdateBankQuestion(ctx context.Context, q *models.BankQuestion) error {
+	query := `UPDATE questions SET question_text = $1, question_type = $2, points = $3,
+                     position = $4, scoring_mode = $5, tags = $6, difficulty = $7
+              WHERE id = $8 AND bank_id IS NOT NULL`
+
+	_, err := r.tx.ExecContext(ctx, query,
+		q.QuestionText, q.QuestionType, q.Points, q.Position,
+		q.ScoringMode, pq.Array(q.Tags), q.Difficulty, q.ID,
+	)
+	return err
+}
+
+// ReplaceBankQuestion переводит ссылки тестов на новую версию вопроса банка
+// и архивирует прежнюю; attempt_questions и ответы попыток не меняются
+func (r *txRepository) ReplaceBankQuestion(ctx context.Context, oldID, newID int) error {
+	query := `UPDATE test_bank_questions SET question_id = $1 WHERE question_id = $2`
+	if _, err := r.tx.ExecContext(ctx, query, newID, oldID); err != nil {
+		return err
+	}
+
+	_, err := r.tx.ExecContext(ctx, `UPDATE questions SET archived = TRUE WHERE id = $1 AND bank_id IS NOT NULL`, oldID)
+	return err
+}
+
+// AddTestBankQuestion добавляет в тест ссылку на вопрос банка
+func (r *txRepository) AddTestBankQuestion(ctx context.Context, testID, questionID, position int, sectionID *int) error {
+	query := `INSERT INTO test_bank_questions (test_id, question_id, position, section_id)
+              VALUES ($1, $2, $3, $4)`
+
+	_, err := r.tx.ExecContext(ctx, query, testID, questionID, position, sectionID)
+	return err
+}
+
+func (r *txRepository) UpdateAnswerOption(ctx context.Context, option *models.AnswerOption) error {
+	query := `UPDATE answer_options SET option_text = $1, is_correct = $2, position = $3 WHERE id = $4`
+
+	_, err := r.tx.ExecContext(ctx, query, option.OptionText, option.IsCorrect, option.Position, option.ID)
+	return err
+}
+
+func (r *txRepository) UpdateMatchingPair(ctx context.Context, pair *models.MatchingPair) error {
+	query := `UPDATE matching_pairs SET left_text = $1, right_text = $2, position = $3 WHERE id = $4`
+
+	_, err := r.tx.ExecContext(ctx, query, pair.LeftText, pair.RightText, pair.Position, pair.ID)
+	return err
+}
+
+func (r *txRepository) DeleteAnswerOption(ctx context.Context, optionID int) error {
+	_, err := r.tx.ExecContext(ctx, `DELETE FROM answer_options WHERE id = $1`, optionID)
+	return err
+}
+
+func (r *txRepository) DeleteMatchingPair(ctx context.Context, pairID int) error {
+	_, err := r.tx.ExecContext(ctx, `DELETE FROM matching_pairs WHERE id = $1`, pairID)
+	return err
+}
+
+// DeleteTextAnswerRules удаляет правила проверки вопроса; они пересоздаются при изменении
+func (r *txRepository) DeleteTextAnswerRules(ctx context.Context, questionID int) error {
+	_, err := r.tx.ExecContext(ctx, `DELETE FROM text_answer_rules WHERE question_id = $1`, questionID)
+	return err
+}
+
+// DeleteRelationSpec удаляет задание вопроса об отношении; оно пересоздается при изменении
+func (r *txRepository) DeleteRelationSpec(ctx context.Context, questionID int) error {
+	_, err := r.tx.ExecContext(ctx, `DELETE FROM relation_specs WHERE question_id = $1`, questionID)
+	return err
+}
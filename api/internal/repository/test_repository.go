@@ -4,11 +4,18 @@ import (
 	"api/internal/models"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+// ErrNotFound возвращается, когда запрошенная запись отсутствует в БД
+var ErrNotFound = errors.New("not found")
+
 type TestRepository struct {
 	Db *sql.DB
 }
@@ -31,20 +38,27 @@ func NewTxRepository(tx *sql.Tx) *txRepository {
 	return &txRepository{tx: tx}
 }
 
+const testColumns = `id, id_course, name, upload_date, ends_date, duration, 
+              attempts, results_released, shuffle_questions, shuffle_options, version, external_id`
+
+func scanTest(row interface{ Scan(...any) error }, test *models.Test) error {
+	return row.Scan(
+		&test.ID, &test.CourseID, &test.Title, &test.UploadDate, &test.EndDate,
+		&test.Duration, &test.Attempts, &test.ResultsReleased,
+		&test.ShuffleQuestions, &test.ShuffleOptions, &test.Version, &test.ExternalID,
+	)
+}
+
 func (r *TestRepository) GetTestByID(ctx context.Context, id string) (*models.Test, error) {
-	query := `SELECT id, name, upload_date, ends_date, duration, 
-              attempts FROM tests WHERE id = $1`
+	query := `SELECT ` + testColumns + ` FROM tests WHERE id = $1 AND deleted_at IS NULL`
 
 	var test models.Test
-	err := r.Db.QueryRowContext(ctx, query, id).Scan(
-		&test.ID, &test.Title, &test.UploadDate, &test.EndDate,
-		&test.Duration, &test.Attempts,
-	)
+	err := scanTest(r.Db.QueryRowContext(ctx, query, id), &test)
 
 	if err != nil {
 		fmt.Println("DB error" + err.Error())
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("test not found")
+			return nil, fmt.Errorf("test %w", ErrNotFound)
 		}
 		return nil, err
 	}
@@ -52,9 +66,57 @@ func (r *TestRepository) GetTestByID(ctx context.Context, id string) (*models.Te
 	return &test, nil
 }
 
+// GetTestByExternalID ищет неудаленный тест по внешнему идентификатору пакета
+func (r *TestRepository) GetTestByExternalID(ctx context.Context, externalID string) (*models.Test, error) {
+	query := `SELECT ` + testColumns + ` FROM tests WHERE external_id = $1 AND deleted_at IS NULL`
+
+	var test models.Test
+	if err := scanTest(r.Db.QueryRowContext(ctx, query, externalID), &test); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("test %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &test, nil
+}
+
+// Колонки вопроса с префиксом q. Для вопросов банка test_id, position и section_id
+// берутся из ссылки теста, поэтому запросы по банку перечисляют колонки сами.
+const questionColumns = `q.id, q.test_id, q.question_text, q.question_type, q.points, q.position,
+              q.scoring_mode, q.section_id, q.bank_id`
+
+func scanQuestion(row interface{ Scan(...any) error }, q *models.Question) error {
+	var sectionID, bankID sql.NullInt64
+	err := row.Scan(&q.ID, &q.TestID, &q.QuestionText, &q.QuestionType, &q.Points, &q.Position,
+		&q.ScoringMode, &sectionID, &bankID)
+	if err != nil {
+		return err
+	}
+
+	if sectionID.Valid {
+		v := int(sectionID.Int64)
+		q.SectionID = &v
+	}
+	if bankID.Valid {
+		v := int(bankID.Int64)
+		q.BankID = &v
+	}
+	return nil
+}
+
+// GetTestQuestions возвращает собственные вопросы теста вместе с вопросами банка,
+// на которые тест ссылается
 func (r *TestRepository) GetTestQuestions(ctx context.Context, testID string) ([]models.Question, error) {
-	query := `SELECT id, test_id, question_text, question_type, points, position 
-              FROM questions WHERE test_id = $1 ORDER BY position`
+	query := `SELECT ` + questionColumns + ` 
+              FROM questions q WHERE q.test_id = $1 AND NOT q.archived
+              UNION ALL
+              SELECT q.id, tb.test_id, q.question_text, q.question_type, q.points, tb.position,
+                     q.scoring_mode, tb.section_id, q.bank_id
+              FROM test_bank_questions tb
+              JOIN questions q ON q.id = tb.question_id
+              WHERE tb.test_id = $1
+              ORDER BY 6, 1`
 
 	rows, err := r.Db.QueryContext(ctx, query, testID)
 	if err != nil {
@@ -65,7 +127,7 @@ func (r *TestRepository) GetTestQuestions(ctx context.Context, testID string) ([
 	var questions []models.Question
 	for rows.Next() {
 		var q models.Question
-		if err := rows.Scan(&q.ID, &q.TestID, &q.QuestionText, &q.QuestionType, &q.Points, &q.Position); err != nil {
+		if err := scanQuestion(rows, &q); err != nil {
 			return nil, err
 		}
 		questions = append(questions, q)
@@ -76,6 +138,58 @@ func (r *TestRepository) GetTestQuestions(ctx context.Context, testID string) ([
 	return questions, nil
 }
 
+// GetTestSections возвращает разделы теста по порядку
+func (r *TestRepository) GetTestSections(ctx context.Context, testID int) ([]models.TestSection, error) {
+	query := `SELECT id, test_id, title, draw_count, position
+              FROM test_sections WHERE test_id = $1 AND NOT archived ORDER BY position, id`
+
+	rows, err := r.Db.QueryContext(ctx, query, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sections []models.TestSection
+	for rows.Next() {
+		var sec models.TestSection
+		if err := rows.Scan(&sec.ID, &sec.TestID, &sec.Title, &sec.DrawCount, &sec.Position); err != nil {
+			return nil, err
+		}
+		sections = append(sections, sec)
+	}
+
+	return sections, rows.Err()
+}
+
+// GetAttemptQuestions возвращает вопросы, выпавшие попытке, в порядке выдачи.
+// Для попыток, начатых до появления пулов, список пуст.
+func (r *TestRepository) GetAttemptQuestions(ctx context.Context, attemptID int) ([]models.Question, error) {
+	query := `SELECT q.id, ta.test_id, q.question_text, q.question_type, q.points, q.position,
+                     q.scoring_mode, q.section_id, q.bank_id
+              FROM attempt_questions aq
+              JOIN test_attempts ta ON ta.id = aq.attempt_id
+              JOIN questions q ON q.id = aq.question_id
+              WHERE aq.attempt_id = $1
+              ORDER BY aq.position`
+
+	rows, err := r.Db.QueryContext(ctx, query, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []models.Question
+	for rows.Next() {
+		var q models.Question
+		if err := scanQuestion(rows, &q); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+
+	return questions, rows.Err()
+}
+
 func (r *TestRepository) GetQuestionOptions(ctx context.Context, questionID int) ([]models.AnswerOption, error) {
 	query := `SELECT id, question_id, option_text, position 
               FROM answer_options WHERE question_id = $1 ORDER BY position`
@@ -98,32 +212,507 @@ func (r *TestRepository) GetQuestionOptions(ctx context.Context, questionID int)
 	return options, nil
 }
 
-func (r *TestRepository) CreateAttempt(ctx context.Context, attempt *models.TestAttempt) error {
-	query := `INSERT INTO test_attempts (user_id, test_id, started_at, status) 
-              VALUES ($1, $2, $3, $4) RETURNING id`
+func (r *TestRepository) GetQuestionByID(ctx context.Context, questionID int) (*models.Question, error) {
+	query := `SELECT q.id, COALESCE(q.test_id, 0), q.question_text, q.question_type, q.points, q.position,
+                     q.scoring_mode, q.section_id, q.bank_id
+              FROM questions q WHERE q.id = $1`
 
-	err := r.Db.QueryRowContext(ctx, query,
-		attempt.UserID, attempt.TestID, attempt.StartedAt, attempt.Status).Scan(&attempt.ID)
-	return err
+	var q models.Question
+	err := scanQuestion(r.Db.QueryRowContext(ctx, query, questionID), &q)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("question %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+// GetQuestionAnswerKey возвращает варианты ответа вместе с признаком правильности.
+// Используется только для проверки ответов, наружу не отдается.
+func (r *TestRepository) GetQuestionAnswerKey(ctx context.Context, questionID int) ([]models.AnswerOption, error) {
+	query := `SELECT id, question_id, option_text, is_correct, position 
+              FROM answer_options WHERE question_id = $1 ORDER BY position`
+
+	rows, err := r.Db.QueryContext(ctx, query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []models.AnswerOption
+	for rows.Next() {
+		var opt models.AnswerOption
+		if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.IsCorrect, &opt.Position); err != nil {
+			return nil, err
+		}
+		options = append(options, opt)
+	}
+
+	return options, rows.Err()
+}
+
+// GetMatchingPairs возвращает пары вопроса на сопоставление в порядке position
+func (r *TestRepository) GetMatchingPairs(ctx context.Context, questionID int) ([]models.MatchingPair, error) {
+	query := `SELECT id, question_id, left_text, right_text, right_key, position 
+              FROM matching_pairs WHERE question_id = $1 ORDER BY position, id`
+
+	rows, err := r.Db.QueryContext(ctx, query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []models.MatchingPair
+	for rows.Next() {
+		var p models.MatchingPair
+		if err := rows.Scan(&p.ID, &p.QuestionID, &p.LeftText, &p.RightText, &p.RightKey, &p.Position); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, p)
+	}
+
+	return pairs, rows.Err()
+}
+
+// GetTextAnswerRules возвращает правила проверки текстового вопроса
+func (r *TestRepository) GetTextAnswerRules(ctx context.Context, questionID int) ([]models.TextAnswerRule, error) {
+	query := `SELECT id, question_id, rule_type, pattern, tolerance, position 
+              FROM text_answer_rules WHERE question_id = $1 ORDER BY position, id`
+
+	rows, err := r.Db.QueryContext(ctx, query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.TextAnswerRule
+	for rows.Next() {
+		var rule models.TextAnswerRule
+		if err := rows.Scan(&rule.ID, &rule.QuestionID, &rule.RuleType, &rule.Pattern, &rule.Tolerance, &rule.Position); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// GetRelationSpec возвращает задание вопроса об отношении
+func (r *TestRepository) GetRelationSpec(ctx context.Context, questionID int) (*models.RelationSpec, error) {
+	query := `SELECT elements, mode, pairs, required, forbidden, exercise, size
+              FROM relation_specs WHERE question_id = $1`
+
+	var spec models.RelationSpec
+	var pairs []byte
+	err := r.Db.QueryRowContext(ctx, query, questionID).Scan(
+		pq.Array(&spec.Elements), &spec.Mode, &pairs, pq.Array(&spec.Required), pq.Array(&spec.Forbidden),
+		&spec.Exercise, &spec.Size,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("relation spec %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(pairs, &spec.Pairs); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
 }
 
+// SaveAnswer сохраняет ответ на вопрос; повторный ответ в той же попытке заменяет предыдущий
 func (r *TestRepository) SaveAnswer(ctx context.Context, answer *models.UserAnswer) error {
-	query := `INSERT INTO user_answers (attempt_id, question_id, answer_data, points_earned)
-              VALUES ($1, $2, $3, $4) RETURNING id`
+	var feedback []byte
+	if answer.Feedback != nil {
+		var err error
+		if feedback, err = json.Marshal(answer.Feedback); err != nil {
+			return err
+		}
+	}
+
+	query := `INSERT INTO user_answers (attempt_id, question_id, answer_data, points_earned, needs_review, feedback, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, NOW())
+              ON CONFLICT (attempt_id, question_id) DO UPDATE SET
+                  answer_data = EXCLUDED.answer_data,
+                  points_earned = EXCLUDED.points_earned,
+                  needs_review = EXCLUDED.needs_review,
+                  feedback = EXCLUDED.feedback,
+                  updated_at = EXCLUDED.updated_at
+              RETURNING id, updated_at`
 
 	err := r.Db.QueryRowContext(ctx, query,
-		answer.AttemptID, answer.QuestionID, answer.AnswerData, answer.PointsEarned).Scan(&answer.ID)
+		answer.AttemptID, answer.QuestionID, answer.AnswerData, answer.PointsEarned, answer.NeedsReview, feedback,
+	).Scan(&answer.ID, &answer.UpdatedAt)
 	return err
 }
 
-func (r *TestRepository) CompleteAttempt(ctx context.Context, attemptID int, score int) error {
-	query := `UPDATE test_attempts SET finished_at = NOW(), score = $1, status = 'completed' 
-              WHERE id = $2`
+// Ответы идут в порядке вопросов, закрепленном за попыткой
+const attemptAnswerOrder = `LEFT JOIN attempt_questions aq ON aq.attempt_id = ua.attempt_id AND aq.question_id = ua.question_id
+              WHERE ua.attempt_id = $1
+              ORDER BY aq.position NULLS LAST, ua.question_id`
 
-	_, err := r.Db.ExecContext(ctx, query, score, attemptID)
+// GetAttemptAnswers возвращает ответы попытки с баллами, комментариями и разбором
+func (r *TestRepository) GetAttemptAnswers(ctx context.Context, attemptID int) ([]models.UserAnswer, error) {
+	query := `SELECT ua.id, ua.attempt_id, ua.question_id, ua.answer_data, ua.points_earned, ua.needs_review,
+                     ua.reviewer_comment, ua.feedback, ua.updated_at
+              FROM user_answers ua
+              ` + attemptAnswerOrder
+
+	rows, err := r.Db.QueryContext(ctx, query, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []models.UserAnswer{}
+	for rows.Next() {
+		var a models.UserAnswer
+		var feedback []byte
+		if err := rows.Scan(&a.ID, &a.AttemptID, &a.QuestionID, &a.AnswerData, &a.PointsEarned, &a.NeedsReview,
+			&a.Comment, &feedback, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if feedback != nil {
+			if err := json.Unmarshal(feedback, &a.Feedback); err != nil {
+				return nil, err
+			}
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
+
+// GetSavedAnswers возвращает сохраненные ответы попытки
+func (r *TestRepository) GetSavedAnswers(ctx context.Context, attemptID int) ([]models.SavedAnswer, error) {
+	query := `SELECT ua.question_id, ua.answer_data, ua.updated_at
+              FROM user_answers ua
+              ` + attemptAnswerOrder
+
+	rows, err := r.Db.QueryContext(ctx, query, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []models.SavedAnswer{}
+	for rows.Next() {
+		var a models.SavedAnswer
+		if err := rows.Scan(&a.QuestionID, &a.AnswerData, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
+
+// CompleteAttempt завершает попытку, если она еще в процессе.
+// Возвращает false, если попытку уже завершили (например, по истечении времени).
+func (r *TestRepository) CompleteAttempt(ctx context.Context, attemptID int, score, maxScore int, percentage float64, status string) (bool, error) {
+	query := `UPDATE test_attempts SET finished_at = NOW(), score = $1, max_score = $2,
+              percentage = $3, status = $4
+              WHERE id = $5 AND status = 'in_progress'`
+
+	res, err := r.Db.ExecContext(ctx, query, score, maxScore, percentage, status, attemptID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ExpireAttempt закрывает просроченную попытку временем ее крайнего срока
+func (r *TestRepository) ExpireAttempt(ctx context.Context, attemptID int, score, maxScore int, percentage float64) (bool, error) {
+	query := `UPDATE test_attempts SET finished_at = deadline_at, score = $1, max_score = $2,
+              percentage = $3, status = 'expired'
+              WHERE id = $4 AND status = 'in_progress'`
+
+	res, err := r.Db.ExecContext(ctx, query, score, maxScore, percentage, attemptID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// UpdateAttemptScore пересчитывает балл уже завершенной попытки, не меняя время
+// завершения; незавершенная попытка не меняется
+func (r *TestRepository) UpdateAttemptScore(ctx context.Context, attemptID int, score, maxScore int, percentage float64, status string) error {
+	query := `UPDATE test_attempts SET score = $1, max_score = $2, percentage = $3, status = $4
+              WHERE id = $5 AND status <> 'in_progress'`
+
+	_, err := r.Db.ExecContext(ctx, query, score, maxScore, percentage, status, attemptID)
 	return err
 }
 
+// CountPendingAnswers возвращает количество ответов попытки, ожидающих ручной проверки
+func (r *TestRepository) CountPendingAnswers(ctx context.Context, attemptID int) (int, error) {
+	query := `SELECT COUNT(*) FROM user_answers WHERE attempt_id = $1 AND needs_review`
+
+	var count int
+	err := r.Db.QueryRowContext(ctx, query, attemptID).Scan(&count)
+	return count, err
+}
+
+func (r *TestRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := `SELECT id, username, role FROM users WHERE username = $1`
+
+	var user models.User
+	err := r.Db.QueryRowContext(ctx, query, username).Scan(&user.Id, &user.Username, &user.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// TeacherHasCourse проверяет, ведет ли преподаватель курс (таблица users_courses)
+func (r *TestRepository) TeacherHasCourse(ctx context.Context, teacherID, courseID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users_courses WHERE id_user = $1 AND id_course = $2)`
+
+	var ok bool
+	err := r.Db.QueryRowContext(ctx, query, teacherID, courseID).Scan(&ok)
+	return ok, err
+}
+
+const pendingAnswerColumns = `ua.id, ua.attempt_id, ua.question_id, u.username, t.id, t.name,
+              c.id, c.name, q.question_text, q.points, ua.answer_data, ta.finished_at`
+
+const pendingAnswerJoins = `FROM user_answers ua
+              JOIN test_attempts ta ON ta.id = ua.attempt_id
+              JOIN users u ON u.id = ta.user_id
+              JOIN questions q ON q.id = ua.question_id
+              JOIN tests t ON t.id = ta.test_id
+              JOIN courses c ON c.id = t.id_course`
+
+func scanPendingAnswer(row interface{ Scan(...any) error }, a *models.PendingAnswer, extra ...any) error {
+	var finishedAt sql.NullTime
+	dest := []any{
+		&a.AnswerID, &a.AttemptID, &a.QuestionID, &a.Username, &a.TestID, &a.TestTitle,
+		&a.CourseID, &a.CourseName, &a.QuestionText, &a.MaxPoints, &a.AnswerData, &finishedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	if finishedAt.Valid {
+		a.FinishedAt = &finishedAt.Time
+	}
+	return nil
+}
+
+// GetPendingAnswers возвращает непроверенные ответы по всем курсам преподавателя
+func (r *TestRepository) GetPendingAnswers(ctx context.Context, teacherID int) ([]models.PendingAnswer, error) {
+	query := `SELECT ` + pendingAnswerColumns + `
+              ` + pendingAnswerJoins + `
+              JOIN users_courses uc ON uc.id_course = c.id
+              WHERE uc.id_user = $1 AND ua.needs_review AND ta.status <> 'in_progress'
+              ORDER BY ta.finished_at, ua.id`
+
+	rows, err := r.Db.QueryContext(ctx, query, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []models.PendingAnswer{}
+	for rows.Next() {
+		var a models.PendingAnswer
+		if err := scanPendingAnswer(rows, &a); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
+
+// GetAnswerForReview возвращает ответ с данными курса и признаком, что он еще не проверен
+func (r *TestRepository) GetAnswerForReview(ctx context.Context, answerID int) (*models.PendingAnswer, bool, error) {
+	query := `SELECT ` + pendingAnswerColumns + `, ua.needs_review
+              ` + pendingAnswerJoins + `
+              WHERE ua.id = $1`
+
+	var a models.PendingAnswer
+	var needsReview bool
+	err := scanPendingAnswer(r.Db.QueryRowContext(ctx, query, answerID), &a, &needsReview)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("answer %w", ErrNotFound)
+		}
+		return nil, false, err
+	}
+
+	return &a, needsReview, nil
+}
+
+// SaveReview сохраняет баллы и комментарий преподавателя за ответ. Возвращает false,
+// если ответ уже проверен (например, другим преподавателем одновременно).
+func (r *TestRepository) SaveReview(ctx context.Context, answerID, points int, comment string, reviewerID int) (bool, error) {
+	query := `UPDATE user_answers SET points_earned = $1, reviewer_comment = $2,
+              reviewed_by = $3, reviewed_at = NOW(), needs_review = FALSE
+              WHERE id = $4 AND needs_review`
+
+	res, err := r.Db.ExecContext(ctx, query, points, comment, reviewerID, answerID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+const attemptColumns = `id, user_id, test_id, started_at, deadline_at, finished_at, score,
+              max_score, percentage, status, shuffle_seed, test_version`
+
+func scanAttempt(row interface{ Scan(...any) error }, attempt *models.TestAttempt) error {
+	var deadlineAt, finishedAt sql.NullTime
+	var score, maxScore sql.NullInt64
+	var percentage sql.NullFloat64
+	err := row.Scan(
+		&attempt.ID, &attempt.UserID, &attempt.TestID, &attempt.StartedAt, &deadlineAt,
+		&finishedAt, &score, &maxScore, &percentage, &attempt.Status, &attempt.ShuffleSeed,
+		&attempt.TestVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	if deadlineAt.Valid {
+		attempt.DeadlineAt = &deadlineAt.Time
+	}
+	if finishedAt.Valid {
+		attempt.FinishedAt = &finishedAt.Time
+	}
+	if score.Valid {
+		v := int(score.Int64)
+		attempt.Score = &v
+	}
+	if maxScore.Valid {
+		v := int(maxScore.Int64)
+		attempt.MaxScore = &v
+	}
+	if percentage.Valid {
+		attempt.Percentage = &percentage.Float64
+	}
+	return nil
+}
+
+func (r *TestRepository) GetAttemptByID(ctx context.Context, attemptID int) (*models.TestAttempt, error) {
+	query := `SELECT ` + attemptColumns + ` FROM test_attempts WHERE id = $1`
+
+	var attempt models.TestAttempt
+	err := scanAttempt(r.Db.QueryRowContext(ctx, query, attemptID), &attempt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("attempt %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// GetActiveAttempt возвращает незавершенную попытку пользователя по тесту
+func (r *TestRepository) GetActiveAttempt(ctx context.Context, userID, testID int) (*models.TestAttempt, error) {
+	query := `SELECT ` + attemptColumns + ` FROM test_attempts
+              WHERE user_id = $1 AND test_id = $2 AND status = 'in_progress'
+              ORDER BY started_at DESC LIMIT 1`
+
+	var attempt models.TestAttempt
+	err := scanAttempt(r.Db.QueryRowContext(ctx, query, userID, testID), &attempt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("attempt %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// GetLatestAttempt возвращает последнюю попытку пользователя по тесту в любом статусе
+func (r *TestRepository) GetLatestAttempt(ctx context.Context, userID, testID int) (*models.TestAttempt, error) {
+	query := `SELECT ` + attemptColumns + ` FROM test_attempts
+              WHERE user_id = $1 AND test_id = $2
+              ORDER BY started_at DESC LIMIT 1`
+
+	var attempt models.TestAttempt
+	err := scanAttempt(r.Db.QueryRowContext(ctx, query, userID, testID), &attempt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("attempt %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// CountUserAttempts возвращает количество попыток пользователя по тесту
+func (r *TestRepository) CountUserAttempts(ctx context.Context, userID, testID int) (int, error) {
+	query := `SELECT COUNT(*) FROM test_attempts WHERE user_id = $1 AND test_id = $2`
+
+	var count int
+	err := r.Db.QueryRowContext(ctx, query, userID, testID).Scan(&count)
+	return count, err
+}
+
+// CountTestAttempts возвращает количество попыток по тесту всех пользователей
+func (r *TestRepository) CountTestAttempts(ctx context.Context, testID int) (int, error) {
+	query := `SELECT COUNT(*) FROM test_attempts WHERE test_id = $1`
+
+	var count int
+	err := r.Db.QueryRowContext(ctx, query, testID).Scan(&count)
+	return count, err
+}
+
+// GetOverdueAttempts возвращает id незавершенных попыток, крайний срок которых раньше before
+func (r *TestRepository) GetOverdueAttempts(ctx context.Context, before time.Time) ([]int, error) {
+	query := `SELECT id FROM test_attempts
+              WHERE status = 'in_progress' AND deadline_at IS NOT NULL AND deadline_at < $1`
+
+	rows, err := r.Db.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// SetResultsReleased открывает или закрывает студентам просмотр теста
+func (r *TestRepository) SetResultsReleased(ctx context.Context, testID int, released bool) error {
+	query := `UPDATE tests SET results_released = $1 WHERE id = $2`
+
+	_, err := r.Db.ExecContext(ctx, query, released, testID)
+	return err
+}
+
+// SumAttemptPoints возвращает сумму баллов, набранных за ответы попытки
+func (r *TestRepository) SumAttemptPoints(ctx context.Context, attemptID int) (int, error) {
+	query := `SELECT COALESCE(SUM(points_earned), 0) FROM user_answers WHERE attempt_id = $1`
+
+	var total int
+	err := r.Db.QueryRowContext(ctx, query, attemptID).Scan(&total)
+	return total, err
+}
+
 func (r *TestRepository) CreateTest(ctx context.Context, test *models.Test) error {
 	query := `INSERT INTO tests (title, end_date, duration, 
               attempts, id_course) 
@@ -138,12 +727,12 @@ func (r *TestRepository) CreateTest(ctx context.Context, test *models.Test) erro
 }
 
 func (r *TestRepository) CreateQuestion(ctx context.Context, question *models.Question) error {
-	query := `INSERT INTO questions (test_id, question_text, question_type, points, position)
-              VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO questions (test_id, question_text, question_type, points, position, scoring_mode)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 
 	err := r.Db.QueryRowContext(ctx, query,
 		question.TestID, question.QuestionText, question.QuestionType,
-		question.Points, question.Position,
+		question.Points, question.Position, question.ScoringMode,
 	).Scan(&question.ID)
 
 	return err
@@ -161,25 +750,27 @@ func (r *TestRepository) CreateAnswerOption(ctx context.Context, option *models.
 }
 
 func (r *txRepository) CreateTest(ctx context.Context, test *models.Test) error {
+	// Без внешнего идентификатора БД назначает новый
 	query := `INSERT INTO tests (name, ends_date, duration, 
-              attempts, id_course) 
-              VALUES ($1, $2, $3, $4, $5) RETURNING id, upload_date`
+              attempts, id_course, shuffle_questions, shuffle_options, external_id) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($8, ''), gen_random_uuid()::text))
+              RETURNING id, upload_date, external_id`
 
 	err := r.tx.QueryRowContext(ctx, query,
 		test.Title, test.EndDate, test.Duration,
-		test.Attempts, test.CourseID,
-	).Scan(&test.ID, &test.UploadDate)
+		test.Attempts, test.CourseID, test.ShuffleQuestions, test.ShuffleOptions, test.ExternalID,
+	).Scan(&test.ID, &test.UploadDate, &test.ExternalID)
 
 	return err
 }
 
 func (r *txRepository) CreateQuestion(ctx context.Context, question *models.Question) error {
-	query := `INSERT INTO questions (test_id, question_text, question_type, points, position)
-              VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO questions (test_id, question_text, question_type, points, position, scoring_mode, section_id)
+              VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 
 	err := r.tx.QueryRowContext(ctx, query,
 		question.TestID, question.QuestionText, question.QuestionType,
-		question.Points, question.Position,
+		question.Points, question.Position, question.ScoringMode, question.SectionID,
 	).Scan(&question.ID)
 
 	return err
@@ -195,3 +786,206 @@ func (r *txRepository) CreateAnswerOption(ctx context.Context, option *models.An
 
 	return err
 }
+
+func (r *txRepository) CreateMatchingPair(ctx context.Context, pair *models.MatchingPair) error {
+	query := `INSERT INTO matching_pairs (question_id, left_text, right_text, right_key, position)
+              VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	err := r.tx.QueryRowContext(ctx, query,
+		pair.QuestionID, pair.LeftText, pair.RightText, pair.RightKey, pair.Position,
+	).Scan(&pair.ID)
+
+	return err
+}
+
+func (r *txRepository) CreateTextAnswerRule(ctx context.Context, rule *models.TextAnswerRule) error {
+	query := `INSERT INTO text_answer_rules (question_id, rule_type, pattern, tolerance, position)
+              VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	err := r.tx.QueryRowContext(ctx, query,
+		rule.QuestionID, rule.RuleType, rule.Pattern, rule.Tolerance, rule.Position,
+	).Scan(&rule.ID)
+
+	return err
+}
+
+func (r *txRepository) CreateRelationSpec(ctx context.Context, questionID int, spec *models.RelationSpec) error {
+	pairs := spec.Pairs
+	if pairs == nil {
+		pairs = []models.RelationPair{}
+	}
+	pairsJSON, err := json.Marshal(pairs)
+	if err != nil {
+		return err
+	}
+
+	// Пустые условия приходят как NULL, а столбцы условий NOT NULL
+	query := `INSERT INTO relation_specs (question_id, elements, mode, pairs, required, forbidden, exercise, size)
+              VALUES ($1, $2, $3, $4, COALESCE($5::TEXT[], '{}'), COALESCE($6::TEXT[], '{}'), $7, $8)`
+
+	_, err = r.tx.ExecContext(ctx, query,
+		questionID, pq.Array(spec.Elements), spec.Mode, pairsJSON, pq.Array(spec.Required), pq.Array(spec.Forbidden),
+		spec.Exercise, spec.Size,
+	)
+	return err
+}
+
+func (r *txRepository) CreateSection(ctx context.Context, section *models.TestSection) error {
+	query := `INSERT INTO test_sections (test_id, title, draw_count, position)
+              VALUES ($1, $2, $3, $4) RETURNING id`
+
+	err := r.tx.QueryRowContext(ctx, query,
+		section.TestID, section.Title, section.DrawCount, section.Position,
+	).Scan(&section.ID)
+
+	return err
+}
+
+func (r *txRepository) CreateAttempt(ctx context.Context, attempt *models.TestAttempt) error {
+	query := `INSERT INTO test_attempts (user_id, test_id, started_at, deadline_at, status, shuffle_seed,
+                                         test_version) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	err := r.tx.QueryRowContext(ctx, query,
+		attempt.UserID, attempt.TestID, attempt.StartedAt, attempt.DeadlineAt, attempt.Status,
+		attempt.ShuffleSeed, attempt.TestVersion).Scan(&attempt.ID)
+	return err
+}
+
+// AddAttemptQuestion закрепляет выпавший вопрос за попыткой
+func (r *txRepository) AddAttemptQuestion(ctx context.Context, attemptID, questionID, position int) error {
+	query := `INSERT INTO attempt_questions (attempt_id, question_id, position) VALUES ($1, $2, $3)`
+
+	_, err := r.tx.ExecContext(ctx, query, attemptID, questionID, position)
+	return err
+}
+
+// UpdateTest сохраняет настройки теста и его версию
+func (r *txRepository) UpdateTest(ctx context.Context, test *models.Test) error {
+	query := `UPDATE tests SET name = $1, ends_date = $2, duration = $3, attempts = $4,
+                     shuffle_questions = $5, shuffle_options = $6, version = $7
+              WHERE id = $8 AND deleted_at IS NULL`
+
+	_, err := r.tx.ExecContext(ctx, query,
+		test.Title, test.EndDate, test.Duration, test.Attempts,
+		test.ShuffleQuestions, test.ShuffleOptions, test.Version, test.ID,
+	)
+	return err
+}
+
+// ArchiveTestContent убирает вопросы и разделы из текущей версии теста, сохраняя
+// их для уже начатых попыток. Ссылки на банк хранятся в attempt_questions попыток,
+// поэтому ссылки теста можно удалить.
+func (r *txRepository) ArchiveTestContent(ctx context.Context, testID int) error {
+	queries := []string{
+		`UPDATE questions SET archived = TRUE WHERE test_id = $1 AND NOT archived`,
+		`UPDATE test_sections SET archived = TRUE WHERE test_id = $1 AND NOT archived`,
+		`DELETE FROM test_bank_questions WHERE test_id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := r.tx.ExecContext(ctx, query, testID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTestContent удаляет вопросы и разделы теста, по которому еще нет попыток
+func (r *txRepository) DeleteTestContent(ctx context.Context, testID int) error {
+	queries := []string{
+		`DELETE FROM test_bank_questions WHERE test_id = $1`,
+		`DELETE FROM answer_options WHERE question_id IN (SELECT id FROM questions WHERE test_id = $1)`,
+		`DELETE FROM questions WHERE test_id = $1`,
+		`DELETE FROM test_sections WHERE test_id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := r.tx.ExecContext(ctx, query, testID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SoftDeleteTest скрывает тест, сохраняя попытки и оценки по нему
+func (r *txRepository) SoftDeleteTest(ctx context.Context, testID int) error {
+	_, err := r.tx.ExecContext(ctx, `UPDATE tests SET deleted_at = NOW() WHERE id = $1`, testID)
+	return err
+}
+
+// DeleteTests полностью удаляет тесты вместе с попытками и ответами
+func (r *txRepository) DeleteTests(ctx context.Context, testIDs []int) error {
+	queries := []string{
+		`DELETE FROM user_answers WHERE attempt_id IN (SELECT id FROM test_attempts WHERE test_id = ANY($1))`,
+		`DELETE FROM test_attempts WHERE test_id = ANY($1)`,
+		`DELETE FROM test_bank_questions WHERE test_id = ANY($1)`,
+		`DELETE FROM answer_options WHERE question_id IN (SELECT id FROM questions WHERE test_id = ANY($1))`,
+		`DELETE FROM questions WHERE test_id = ANY($1)`,
+		`DELETE FROM test_sections WHERE test_id = ANY($1)`,
+		`DELETE FROM tests WHERE id = ANY($1)`,
+	}
+	for _, query := range queries {
+		if _, err := r.tx.ExecContext(ctx, query, pq.Array(testIDs)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCourse удаляет курс вместе с тестами в одной транзакции. Тесты с попытками
+// только скрываются, как при удалении отдельного теста, чтобы не потерять оценки.
+func (r *TestRepository) DeleteCourse(ctx context.Context, courseID int) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT t.id, EXISTS(SELECT 1 FROM test_attempts a WHERE a.test_id = t.id)
+              FROM tests t
+              WHERE t.id_course = $1 AND t.deleted_at IS NULL`
+
+	rows, err := tx.QueryContext(ctx, query, courseID)
+	if err != nil {
+		return err
+	}
+	var withAttempts, withoutAttempts []int
+	for rows.Next() {
+		var id int
+		var hasAttempts bool
+		if err := rows.Scan(&id, &hasAttempts); err != nil {
+			rows.Close()
+			return err
+		}
+		if hasAttempts {
+			withAttempts = append(withAttempts, id)
+		} else {
+			withoutAttempts = append(withoutAttempts, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	txRepo := NewTxRepository(tx)
+	for _, id := range withAttempts {
+		if err := txRepo.SoftDeleteTest(ctx, id); err != nil {
+			return err
+		}
+	}
+	if err := txRepo.DeleteTests(ctx, withoutAttempts); err != nil {
+		return err
+	}
+
+	queries := []string{
+		`DELETE FROM users_courses WHERE id_course = $1`,
+		`DELETE FROM groups_courses WHERE id_course = $1`,
+		`DELETE FROM courses WHERE id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, courseID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
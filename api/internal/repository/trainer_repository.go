@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"api/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const trainerRelationColumns = `id, name, elements, pairs, created_at, updated_at`
+
+func scanTrainerRelation(row interface{ Scan(...any) error }, rel *models.TrainerRelation) error {
+	var pairs []byte
+	if err := row.Scan(&rel.ID, &rel.Name, pq.Array(&rel.Elements), &pairs, &rel.CreatedAt, &rel.UpdatedAt); err != nil {
+		return err
+	}
+	return json.Unmarshal(pairs, &rel.Pairs)
+}
+
+// SaveTrainerRelation сохраняет отношение тренажера; отношение пользователя
+// с тем же именем перезаписывается
+func (r *TestRepository) SaveTrainerRelation(ctx context.Context, userID int, rel *models.TrainerRelation) error {
+	pairs := rel.Pairs
+	if pairs == nil {
+		pairs = []models.RelationPair{}
+	}
+	pairsJSON, err := json.Marshal(pairs)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO trainer_relations (user_id, name, elements, pairs)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (user_id, name) DO UPDATE
+              SET elements = EXCLUDED.elements, pairs = EXCLUDED.pairs, updated_at = NOW()
+              RETURNING id, created_at, updated_at`
+
+	return r.Db.QueryRowContext(ctx, query, userID, rel.Name, pq.Array(rel.Elements), pairsJSON).
+		Scan(&rel.ID, &rel.CreatedAt, &rel.UpdatedAt)
+}
+
+// GetTrainerRelations возвращает отношения пользователя, последние измененные первыми
+func (r *TestRepository) GetTrainerRelations(ctx context.Context, userID int) ([]models.TrainerRelation, error) {
+	query := `SELECT ` + trainerRelationColumns + ` FROM trainer_relations
+              WHERE user_id = $1 ORDER BY updated_at DESC, id DESC`
+
+	rows, err := r.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := []models.TrainerRelation{}
+	for rows.Next() {
+		var rel models.TrainerRelation
+		if err := scanTrainerRelation(rows, &rel); err != nil {
+			return nil, err
+		}
+		relations = append(relations, rel)
+	}
+
+	return relations, rows.Err()
+}
+
+// GetTrainerRelation возвращает отношение, если оно принадлежит пользователю
+func (r *TestRepository) GetTrainerRelation(ctx context.Context, userID, relationID int) (*models.TrainerRelation, error) {
+	query := `SELECT ` + trainerRelationColumns + ` FROM trainer_relations WHERE id = $1 AND user_id = $2`
+
+	var rel models.TrainerRelation
+	if err := scanTrainerRelation(r.Db.QueryRowContext(ctx, query, relationID, userID), &rel); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("trainer relation %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &rel, nil
+}
+
+func (r *TestRepository) DeleteTrainerRelation(ctx context.Context, userID, relationID int) error {
+	query := `DELETE FROM trainer_relations WHERE id = $1 AND user_id = $2`
+
+	res, err := r.Db.ExecContext(ctx, query, relationID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("trainer relation %w", ErrNotFound)
+	}
+	return nil
+}
+
+// LogTrainerCheck записывает проверку свойств в журнал тренажера
+func (r *TestRepository) LogTrainerCheck(ctx context.Context, check *models.TrainerCheck) error {
+	query := `INSERT INTO trainer_checks (user_id, relation_key, condition, holds) VALUES ($1, $2, $3, $4)`
+
+	_, err := r.Db.ExecContext(ctx, query, check.UserID, check.RelationKey, check.Condition, check.Holds)
+	return err
+}
+
+// GetTeacherGroups возвращает группы, которые учатся на курсах преподавателя
+func (r *TestRepository) GetTeacherGroups(ctx context.Context, teacherID int) ([]models.Group, error) {
+	query := `SELECT DISTINCT g.id, g.name FROM groups g
+              JOIN groups_courses gc ON gc.id_group = g.id
+              JOIN users_courses uc ON uc.id_course = gc.id_course
+              WHERE uc.id_user = $1
+              ORDER BY g.name, g.id`
+
+	rows, err := r.Db.QueryContext(ctx, query, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.Group{}
+	for rows.Next() {
+		var g models.Group
+		if err := rows.Scan(&g.Id, &g.Name); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	return groups, rows.Err()
+}
+
+// Проверки тренажера за период; пустые границы не ограничивают
+const periodChecks = `SELECT user_id, relation_key, condition, checked_at FROM trainer_checks
+              WHERE ($2::TIMESTAMPTZ IS NULL OR checked_at >= $2) AND ($3::TIMESTAMPTZ IS NULL OR checked_at < $3)`
+
+// GetGroupPractice собирает практику студентов группы в тренажере за период,
+// решенные задания и завершенные попытки тестов по курсам преподавателя. Время считается
+// по промежуткам между соседними проверками студента не длиннее idleGap.
+func (r *TestRepository) GetGroupPractice(ctx context.Context, teacherID, groupID int, from, to *time.Time, idleGap time.Duration) ([]models.StudentPractice, error) {
+	query := `SELECT u.id, u.username,
+                     COALESCE(c.seconds, 0), COALESCE(c.relations, 0), COALESCE(c.checks, 0), c.last_check,
+                     COALESCE(s.saved, 0), COALESCE(t.solved, 0), COALESCE(a.attempts, 0), a.average
+              FROM users u
+              LEFT JOIN (
+                  SELECT user_id,
+                         SUM(CASE WHEN gap <= $4 THEN gap ELSE 0 END)::INTEGER AS seconds,
+                         COUNT(DISTINCT relation_key) AS relations,
+                         COUNT(*) AS checks,
+                         MAX(checked_at) AS last_check
+                  FROM (
+                      SELECT user_id, relation_key, checked_at,
+                             EXTRACT(EPOCH FROM checked_at - LAG(checked_at) OVER (PARTITION BY user_id ORDER BY checked_at)) AS gap
+                      FROM (` + periodChecks + `) period
+                  ) gaps
+                  GROUP BY user_id
+              ) c ON c.user_id = u.id
+              LEFT JOIN (
+                  SELECT user_id, COUNT(*) AS saved FROM trainer_relations GROUP BY user_id
+              ) s ON s.user_id = u.id
+              LEFT JOIN (
+                  SELECT user_id, COUNT(*) AS solved FROM trainer_task_progress
+                  WHERE solved_at IS NOT NULL GROUP BY user_id
+              ) t ON t.user_id = u.id
+              LEFT JOIN (
+                  SELECT ta.user_id, COUNT(*) AS attempts, AVG(ta.percentage) AS average
+                  FROM test_attempts ta
+                  JOIN tests t ON t.id = ta.test_id
+                  JOIN users_courses uc ON uc.id_course = t.id_course AND uc.id_user = $5
+                  WHERE ta.status IN ('completed', 'expired')
+                  GROUP BY ta.user_id
+              ) a ON a.user_id = u.id
+              WHERE u.id_group = $1 AND u.role = 'student'
+              ORDER BY u.username`
+
+	rows, err := r.Db.QueryContext(ctx, query, groupID, from, to, idleGap.Seconds(), teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []models.StudentPractice{}
+	for rows.Next() {
+		var p models.StudentPractice
+		var lastCheck sql.NullTime
+		var average sql.NullFloat64
+		if err := rows.Scan(&p.UserID, &p.Username, &p.TimeSpentSeconds, &p.RelationsExplored, &p.Checks, &lastCheck,
+			&p.SavedRelations, &p.SolvedTasks, &p.FinishedAttempts, &average); err != nil {
+			return nil, err
+		}
+		if lastCheck.Valid {
+			p.LastActivity = &lastCheck.Time
+		}
+		if average.Valid {
+			p.AveragePercentage = &average.Float64
+		}
+		p.ChecksByCondition = map[string]int{}
+		students = append(students, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return students, r.countGroupChecks(ctx, groupID, from, to, students)
+}
+
+// countGroupChecks заполняет число проверок по каждому свойству;
+// проверки всех свойств сразу считаются под ключом all
+func (r *TestRepository) countGroupChecks(ctx context.Context, groupID int, from, to *time.Time, students []models.StudentPractice) error {
+	query := `SELECT c.user_id, COALESCE(NULLIF(c.condition, ''), 'all'), COUNT(*)
+              FROM (` + periodChecks + `) c
+              JOIN users u ON u.id = c.user_id
+              WHERE u.id_group = $1
+              GROUP BY 1, 2`
+
+	rows, err := r.Db.QueryContext(ctx, query, groupID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byUser := make(map[int]map[string]int, len(students))
+	for _, p := range students {
+		byUser[p.UserID] = p.ChecksByCondition
+	}
+	for rows.Next() {
+		var userID, count int
+		var condition string
+		if err := rows.Scan(&userID, &condition, &count); err != nil {
+			return err
+		}
+		if counts, ok := byUser[userID]; ok {
+			counts[condition] = count
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetTrainerTaskProgress возвращает прогресс пользователя по заданиям тренажера
+func (r *TestRepository) GetTrainerTaskProgress(ctx context.Context, userID int) (map[string]models.TrainerTaskProgress, error) {
+	query := `SELECT task_id, attempts, solved_at FROM trainer_task_progress WHERE user_id = $1`
+
+	rows, err := r.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := map[string]models.TrainerTaskProgress{}
+	for rows.Next() {
+		var taskID string
+		var p models.TrainerTaskProgress
+		var solvedAt sql.NullTime
+		if err := rows.Scan(&taskID, &p.Attempts, &solvedAt); err != nil {
+			return nil, err
+		}
+		if solvedAt.Valid {
+			p.SolvedAt = &solvedAt.Time
+		}
+		progress[taskID] = p
+	}
+
+	return progress, rows.Err()
+}
+
+// RecordTrainerTaskAttempt засчитывает проверку решения задания; время решения
+// записывается при первом верном решении и дальше не меняется
+func (r *TestRepository) RecordTrainerTaskAttempt(ctx context.Context, userID int, taskID string, solved bool) (*models.TrainerTaskProgress, error) {
+	query := `INSERT INTO trainer_task_progress (user_id, task_id, attempts, solved_at)
+              VALUES ($1, $2, 1, CASE WHEN $3 THEN NOW() END)
+              ON CONFLICT (user_id, task_id) DO UPDATE
+              SET attempts = trainer_task_progress.attempts + 1,
+                  solved_at = COALESCE(trainer_task_progress.solved_at, EXCLUDED.solved_at),
+                  updated_at = NOW()
+              RETURNING attempts, solved_at`
+
+	var p models.TrainerTaskProgress
+	var solvedAt sql.NullTime
+	if err := r.Db.QueryRowContext(ctx, query, userID, taskID, solved).Scan(&p.Attempts, &solvedAt); err != nil {
+		return nil, err
+	}
+	if solvedAt.Valid {
+		p.SolvedAt = &solvedAt.Time
+	}
+	return &p, nil
+}
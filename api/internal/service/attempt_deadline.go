@@ -0,0 +1,79 @@
+package service
+
+import (
+	"api/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// Запас времени на доставку ответа, отправленного в последний момент
+const submitGracePeriod = 30 * time.Second
+
+// attemptDeadline вычисляет крайний срок попытки: начало + длительность теста,
+// но не позже даты окончания теста. nil — попытка не ограничена по времени.
+func attemptDeadline(test *models.Test, startedAt time.Time) *time.Time {
+	var deadline *time.Time
+	if test.Duration > 0 {
+		d := startedAt.Add(time.Duration(test.Duration) * time.Second)
+		deadline = &d
+	}
+	if !test.EndDate.IsZero() && (deadline == nil || test.EndDate.Before(*deadline)) {
+		d := test.EndDate
+		deadline = &d
+	}
+	return deadline
+}
+
+// attemptOverdue сообщает, истекло ли время попытки с учетом запаса на доставку
+func attemptOverdue(attempt *models.TestAttempt, now time.Time) bool {
+	return attempt.DeadlineAt != nil && now.After(attempt.DeadlineAt.Add(submitGracePeriod))
+}
+
+// RunAttemptSweeper периодически закрывает просроченные попытки со статусом expired
+// и подсчитывает их баллы. Работает до отмены ctx.
+func (s *TestService) RunAttemptSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.expireOverdueAttempts(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TestService) expireOverdueAttempts(ctx context.Context) {
+	ids, err := s.Repo.GetOverdueAttempts(ctx, time.Now().Add(-submitGracePeriod))
+	if err != nil {
+		log.Println("Ошибка получения просроченных попыток " + err.Error())
+		return
+	}
+
+	for _, id := range ids {
+		if err := s.expireAttempt(ctx, id); err != nil {
+			log.Println("Ошибка завершения просроченной попытки " + strconv.Itoa(id) + ": " + err.Error())
+			continue
+		}
+
+		log.Println("Попытка " + strconv.Itoa(id) + " завершена по истечении времени")
+	}
+}
+
+// expireAttempt подсчитывает баллы просроченной попытки и закрывает ее со статусом
+// expired. Попытка, уже закрытая другим запросом, не меняется.
+func (s *TestService) expireAttempt(ctx context.Context, attemptID int) error {
+	score, maxScore, err := s.calculateAttemptScore(ctx, attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to calculate score: %w", err)
+	}
+
+	_, err = s.Repo.ExpireAttempt(ctx, attemptID, score, maxScore, scorePercentage(score, maxScore))
+	return err
+}
@@ -58,6 +58,29 @@ func debugToken(tokenString string) {
 	}
 }
 
+// GetUsernameFromToken проверяет подпись и срок действия access-токена
+// и возвращает имя пользователя из него
+func GetUsernameFromToken(tokenString string) (string, error) {
+	claims := &CustomClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return accessTokenSecret, nil
+	})
+
+	if err != nil || !token.Valid {
+		return "", fmt.Errorf("invalid token: %v", err)
+	}
+	if claims.Username == "" {
+		return "", fmt.Errorf("invalid token: empty username")
+	}
+
+	return claims.Username, nil
+}
+
+// GetUsernameGromToken достает имя пользователя БЕЗ проверки подписи.
+// Для проверки прав используйте GetUsernameFromToken.
 func GetUsernameGromToken(tokenString string) string {
 	token, _, _ := jwt.NewParser().ParseUnverified(tokenString, &CustomClaims{})
 	return token.Claims.(*CustomClaims).Username
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"api/internal/models"
+	"api/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetCourseBanks возвращает банки курса, доступные преподавателю
+func (s *TestService) GetCourseBanks(ctx context.Context, teacherID, courseID int) ([]models.QuestionBank, error) {
+	if err := s.checkCourseTeacher(ctx, teacherID, courseID); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetCourseBanks(ctx, courseID, teacherID)
+}
+
+// GetBank возвращает банк с вопросами и ключами ответов
+func (s *TestService) GetBank(ctx context.Context, teacherID, bankID int, filter models.BankQuestionFilter) (*models.QuestionBank, error) {
+	bank, err := s.accessibleBank(ctx, teacherID, bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	bank.Questions, err = s.Repo.GetBankQuestions(ctx, bankID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bank.Questions {
+		if err := s.loadAnswerKey(ctx, &bank.Questions[i].TeacherQuestion); err != nil {
+			return nil, err
+		}
+	}
+
+	return bank, nil
+}
+
+func (s *TestService) CreateBank(ctx context.Context, teacherID int, req *models.BankRequest) (*models.QuestionBank, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("%w: bank title is required", ErrInvalidInput)
+	}
+	if err := s.checkCourseTeacher(ctx, teacherID, req.CourseID); err != nil {
+		return nil, err
+	}
+
+	bank := &models.QuestionBank{
+		CourseID: req.CourseID,
+		OwnerID:  teacherID,
+		Title:    strings.TrimSpace(req.Title),
+		Shared:   req.Shared,
+	}
+	if err := s.Repo.CreateBank(ctx, bank); err != nil {
+		return nil, err
+	}
+
+	return bank, nil
+}
+
+// UpdateBank меняет название и общий доступ; это может только владелец банка
+func (s *TestService) UpdateBank(ctx context.Context, teacherID int, req *models.BankRequest) (*models.QuestionBank, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("%w: bank title is required", ErrInvalidInput)
+	}
+
+	bank, err := s.ownedBank(ctx, teacherID, req.BankID)
+	if err != nil {
+		return nil, err
+	}
+
+	bank.Title = strings.TrimSpace(req.Title)
+	bank.Shared = req.Shared
+	if err := s.Repo.UpdateBank(ctx, bank); err != nil {
+		return nil, err
+	}
+
+	return bank, nil
+}
+
+// DeleteBank удаляет банк владельца, если его вопросы не используются в тестах
+// и не попадали в попытки: удаление банка удалило бы их из истории попыток
+func (s *TestService) DeleteBank(ctx context.Context, teacherID, bankID int) error {
+	if _, err := s.ownedBank(ctx, teacherID, bankID); err != nil {
+		return err
+	}
+
+	uses, err := s.Repo.CountBankUses(ctx, bankID)
+	if err != nil {
+		return err
+	}
+	if uses > 0 {
+		return fmt.Errorf("%w: bank questions are used in tests %d times", ErrConflict, uses)
+	}
+	attempts, err := s.Repo.CountBankAttempts(ctx, bankID)
+	if err != nil {
+		return err
+	}
+	if attempts > 0 {
+		return fmt.Errorf("%w: bank questions were answered in %d attempts", ErrConflict, attempts)
+	}
+
+	return s.Repo.DeleteBank(ctx, bankID)
+}
+
+func (s *TestService) CreateBankQuestion(ctx context.Context, teacherID int, req *models.BankQuestionRequest) (*models.BankQuestion, error) {
+	if _, err := s.accessibleBank(ctx, teacherID, req.BankID); err != nil {
+		return nil, err
+	}
+
+	question, err := bankQuestionFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	question.BankID = &req.BankID
+
+	tx, err := s.Repo.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txRepo := repository.NewTxRepository(tx)
+
+	if err := txRepo.CreateBankQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to create bank question: %w", err)
+	}
+	if err := createQuestionContent(ctx, txRepo, question.ID, req.Question); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.bankQuestionWithKey(ctx, question.ID)
+}
+
+// UpdateBankQuestion изменяет вопрос банка во всех тестах, которые на него ссылаются.
+// Варианты ответа и пары обновляются по порядку, поэтому их id (и сохраненные
+// ответы студентов) остаются действительными; лишние удаляются, новые добавляются.
+// Вопрос, который уже попадал в попытки, не меняется: изменения сохраняются в копию
+// (см. copyBankQuestion), а прежняя версия остается у попыток.
+func (s *TestService) UpdateBankQuestion(ctx context.Context, teacherID int, req *models.BankQuestionRequest) (*models.BankQuestion, error) {
+	existing, err := s.Repo.GetBankQuestion(ctx, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.accessibleBank(ctx, teacherID, *existing.BankID); err != nil {
+		return nil, err
+	}
+
+	question, err := bankQuestionFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if existing.AttemptUses > 0 {
+		question.BankID = existing.BankID
+		return s.copyBankQuestion(ctx, existing.ID, question, req.Question)
+	}
+	question.ID = existing.ID
+
+	options, err := s.Repo.GetQuestionAnswerKey(ctx, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := s.Repo.GetMatchingPairs(ctx, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.Repo.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txRepo := repository.NewTxRepository(tx)
+
+	if err := txRepo.UpdateBankQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to update bank question: %w", err)
+	}
+
+	// Правила проверки и задание отношения не имеют id в ответах студентов, их проще пересоздать
+	if err := txRepo.DeleteTextAnswerRules(ctx, question.ID); err != nil {
+		return nil, err
+	}
+	if err := txRepo.DeleteRelationSpec(ctx, question.ID); err != nil {
+		return nil, err
+	}
+
+	content := req.Question
+	content.Options, content.Pairs = nil, nil
+
+	for i, optReq := range req.Question.Options {
+		if i >= len(options) {
+			content.Options = append(content.Options, optReq)
+			continue
+		}
+		option := options[i]
+		option.OptionText, option.IsCorrect, option.Position = optReq.OptionText, optReq.IsCorrect, optReq.Position
+		if err := txRepo.UpdateAnswerOption(ctx, &option); err != nil {
+			return nil, err
+		}
+	}
+	for _, option := range options[min(len(options), len(req.Question.Options)):] {
+		if err := txRepo.DeleteAnswerOption(ctx, option.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, pairReq := range req.Question.Pairs {
+		if i >= len(pairs) {
+			content.Pairs = append(content.Pairs, pairReq)
+			continue
+		}
+		pair := pairs[i]
+		pair.LeftText, pair.RightText, pair.Position = pairReq.LeftText, pairReq.RightText, pairReq.Position
+		if err := txRepo.UpdateMatchingPair(ctx, &pair); err != nil {
+			return nil, err
+		}
+	}
+	for _, pair := range pairs[min(len(pairs), len(req.Question.Pairs)):] {
+		if err := txRepo.DeleteMatchingPair(ctx, pair.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := createQuestionContent(ctx, txRepo, question.ID, content); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.bankQuestionWithKey(ctx, question.ID)
+}
+
+// copyBankQuestion сохраняет измененный вопрос банка новой версией: тесты начинают
+// ссылаться на копию, а прежний вопрос архивируется. Попытки и ответы студентов
+// остаются привязанными к прежнему вопросу, и ключ для их пересчета и просмотра
+// не меняется.
+func (s *TestService) copyBankQuestion(ctx context.Context, oldID int, question *models.BankQuestion, content models.CreateQuestionRequest) (*models.BankQuestion, error) {
+	tx, err := s.Repo.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txRepo := repository.NewTxRepository(tx)
+
+	if err := txRepo.CreateBankQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to create bank question: %w", err)
+	}
+	if err := createQuestionContent(ctx, txRepo, question.ID, content); err != nil {
+		return nil, err
+	}
+	if err := txRepo.ReplaceBankQuestion(ctx, oldID, question.ID); err != nil {
+		return nil, fmt.Errorf("failed to replace bank question: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.bankQuestionWithKey(ctx, question.ID)
+}
+
+// DeleteBankQuestion удаляет вопрос банка, если ни один тест на него не ссылается.
+// Вопрос, который попадал в попытки, только архивируется: попытки и ответы
+// студентов ссылаются на него, и удаление стерло бы их историю.
+func (s *TestService) DeleteBankQuestion(ctx context.Context, teacherID, questionID int) error {
+	existing, err := s.Repo.GetBankQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.accessibleBank(ctx, teacherID, *existing.BankID); err != nil {
+		return err
+	}
+	if existing.UsedIn > 0 {
+		return fmt.Errorf("%w: question %d is used in %d tests", ErrConflict, questionID, existing.UsedIn)
+	}
+	if existing.AttemptUses > 0 {
+		return s.Repo.ArchiveBankQuestion(ctx, questionID)
+	}
+
+	return s.Repo.DeleteBankQuestion(ctx, questionID)
+}
+
+// accessibleBank возвращает банк, если преподаватель его владелец или банк общий
+// и преподаватель ведет курс
+func (s *TestService) accessibleBank(ctx context.Context, teacherID, bankID int) (*models.QuestionBank, error) {
+	bank, err := s.Repo.GetBank(ctx, bankID)
+	if err != nil {
+		return nil, err
+	}
+	if bank.OwnerID == teacherID {
+		return bank, nil
+	}
+	if !bank.Shared {
+		return nil, fmt.Errorf("%w: question bank %d is private", ErrForbidden, bankID)
+	}
+	if err := s.checkCourseTeacher(ctx, teacherID, bank.CourseID); err != nil {
+		return nil, err
+	}
+	return bank, nil
+}
+
+func (s *TestService) ownedBank(ctx context.Context, teacherID, bankID int) (*models.QuestionBank, error) {
+	bank, err := s.Repo.GetBank(ctx, bankID)
+	if err != nil {
+		return nil, err
+	}
+	if bank.OwnerID != teacherID {
+		return nil, fmt.Errorf("%w: question bank %d belongs to another teacher", ErrForbidden, bankID)
+	}
+	return bank, nil
+}
+
+// checkBankQuestion проверяет, что тест ссылается на вопрос банка своего курса,
+// доступного преподавателю: чужой закрытый банк выдал бы ему ключи ответов
+func (s *TestService) checkBankQuestion(ctx context.Context, teacherID, questionID, courseID int) error {
+	question, err := s.Repo.GetBankQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	bank, err := s.accessibleBank(ctx, teacherID, *question.BankID)
+	if err != nil {
+		return err
+	}
+	if bank.CourseID != courseID {
+		return fmt.Errorf("%w: bank question %d belongs to another course", ErrInvalidInput, questionID)
+	}
+	return nil
+}
+
+func (s *TestService) bankQuestionWithKey(ctx context.Context, questionID int) (*models.BankQuestion, error) {
+	question, err := s.Repo.GetBankQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadAnswerKey(ctx, &question.TeacherQuestion); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// bankQuestionFromRequest проверяет запрос и собирает вопрос банка без содержимого
+func bankQuestionFromRequest(req *models.BankQuestionRequest) (*models.BankQuestion, error) {
+	normalizeQuestion(&req.Question)
+	if err := validateQuestion(req.Question); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if req.Difficulty < 0 || req.Difficulty > 5 {
+		return nil, fmt.Errorf("%w: difficulty must be between 0 and 5", ErrInvalidInput)
+	}
+
+	question := &models.BankQuestion{
+		Tags:       normalizeTags(req.Tags),
+		Difficulty: req.Difficulty,
+	}
+	question.QuestionText = req.Question.QuestionText
+	question.QuestionType = req.Question.QuestionType
+	question.Points = req.Question.Points
+	question.Position = req.Question.Position
+	question.ScoringMode = req.Question.ScoringMode
+	if question.ScoringMode == "" {
+		question.ScoringMode = scoringAllOrNothing
+	}
+
+	return question, nil
+}
+
+// normalizeTags убирает пустые теги и повторы, приводя теги к нижнему регистру
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
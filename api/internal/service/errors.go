@@ -0,0 +1,14 @@
+package service
+
+import (
+	"api/internal/repository"
+	"errors"
+)
+
+// Ошибки сервиса, по которым обработчики выбирают HTTP-статус
+var (
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = repository.ErrNotFound
+	ErrConflict     = errors.New("conflict")
+	ErrInvalidInput = errors.New("invalid input")
+)
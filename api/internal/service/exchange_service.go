@@ -0,0 +1,179 @@
+package service
+
+import (
+	"api/internal/exchange"
+	"api/internal/models"
+	"api/internal/repository"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Название импортированного теста, если его нет ни в запросе, ни в файле
+const defaultImportTitle = "Импортированный тест"
+
+// ExportTest выгружает тест с ключом ответов в Moodle XML или GIFT.
+// Разделы с пулами не переносятся: в файл попадают все вопросы теста.
+func (s *TestService) ExportTest(ctx context.Context, teacherID, testID int, format string) ([]byte, error) {
+	test, questions, err := s.GetTeacherTest(ctx, teacherID, strconv.Itoa(testID))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := exchange.Export(format, test, questions)
+	if errors.Is(err, exchange.ErrUnknownFormat) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return data, err
+}
+
+// ImportTest разбирает файл Moodle XML или GIFT и создает из него тест курса.
+// Вопросы, которые не прошли проверку, пропускаются и попадают в отчет.
+// При DryRun отчет возвращается без сохранения.
+func (s *TestService) ImportTest(ctx context.Context, teacherID int, req *models.ImportTestRequest) (*models.ImportReport, error) {
+	if err := s.checkCourseTeacher(ctx, teacherID, req.CourseID); err != nil {
+		return nil, err
+	}
+
+	result, err := exchange.Import(req.Format, []byte(req.Content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	report := &models.ImportReport{
+		DryRun:    req.DryRun,
+		Issues:    result.Issues,
+		Skipped:   result.Skipped,
+		Questions: []models.CreateQuestionRequest{},
+	}
+
+	for _, imported := range result.Questions {
+		question := imported.Question
+		normalizeQuestion(&question)
+		if err := validateQuestion(question); err != nil {
+			report.Skipped++
+			report.Issues = append(report.Issues, models.ImportIssue{
+				Question: imported.Index,
+				Title:    imported.Title,
+				Severity: exchange.SeveritySkipped,
+				Message:  err.Error(),
+			})
+			continue
+		}
+
+		question.Position = len(report.Questions) + 1
+		report.Questions = append(report.Questions, question)
+	}
+	report.Imported = len(report.Questions)
+	if report.Issues == nil {
+		report.Issues = []models.ImportIssue{}
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Question < report.Issues[j].Question
+	})
+
+	if req.DryRun {
+		return report, nil
+	}
+	if report.Imported == 0 {
+		return nil, fmt.Errorf("%w: file contains no supported questions", ErrInvalidInput)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = result.Title
+	}
+	if title == "" {
+		title = defaultImportTitle
+	}
+
+	report.Test, err = s.CreateTest(ctx, teacherID, &models.CreateTestRequest{
+		CourseID:  req.CourseID,
+		Title:     title,
+		Duration:  req.Duration,
+		Attempts:  req.Attempts,
+		EndDate:   req.EndDate,
+		Questions: report.Questions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Результаты импорта пакета
+const (
+	packageCreated   = "created"
+	packageUpdated   = "updated"
+	packageUnchanged = "unchanged"
+)
+
+// ExportPackage выгружает тест в собственном формате пакета
+func (s *TestService) ExportPackage(ctx context.Context, teacherID, testID int) ([]byte, error) {
+	test, questions, err := s.GetTeacherTest(ctx, teacherID, strconv.Itoa(testID))
+	if err != nil {
+		return nil, err
+	}
+
+	return exchange.NewPackage(test, questions).Marshal()
+}
+
+// ImportPackage создает тест из пакета или обновляет тест с тем же внешним
+// идентификатором. Повторный импорт того же пакета ничего не меняет: текущий тест
+// выгружается и сравнивается с пакетом, и новая версия создается только при отличиях.
+func (s *TestService) ImportPackage(ctx context.Context, teacherID int, req *models.ImportPackageRequest) (*models.ImportPackageResult, error) {
+	if err := s.checkCourseTeacher(ctx, teacherID, req.CourseID); err != nil {
+		return nil, err
+	}
+
+	pkg, err := exchange.ParsePackage(req.Package)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	createReq := pkg.CreateRequest()
+	createReq.CourseID = req.CourseID
+	if err := s.validateTestRequest(ctx, teacherID, &createReq); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repo.GetTestByExternalID(ctx, pkg.Test.ExternalID)
+	if errors.Is(err, repository.ErrNotFound) {
+		test, err := s.CreateTest(ctx, teacherID, &createReq)
+		if err != nil {
+			return nil, err
+		}
+		return &models.ImportPackageResult{Status: packageCreated, Test: test}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.CourseID != req.CourseID {
+		return nil, fmt.Errorf("%w: test %s belongs to another course", ErrConflict, pkg.Test.ExternalID)
+	}
+
+	current, err := s.ExportPackage(ctx, teacherID, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	incoming, err := pkg.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(current, incoming) {
+		return &models.ImportPackageResult{Status: packageUnchanged, Test: existing}, nil
+	}
+
+	createReq.EndDate = pkg.EndDate(existing.UploadDate)
+	test, err := s.UpdateTest(ctx, teacherID, &models.UpdateTestRequest{TestID: existing.ID, CreateTestRequest: createReq})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ImportPackageResult{Status: packageUpdated, Test: test}, nil
+}
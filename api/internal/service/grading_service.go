@@ -0,0 +1,73 @@
+package service
+
+import (
+	"api/internal/models"
+	"context"
+	"fmt"
+)
+
+// GetGradingQueue возвращает ответы, ожидающие ручной проверки, по курсам преподавателя
+func (s *TestService) GetGradingQueue(ctx context.Context, teacherID int) ([]models.PendingAnswer, error) {
+	return s.Repo.GetPendingAnswers(ctx, teacherID)
+}
+
+// GradeAnswer выставляет баллы за ответ. Когда проверен последний ответ попытки,
+// балл попытки пересчитывается, и она переходит в статус completed. Ответы попытки,
+// которую студент еще не завершил, не проверяются: он может их изменить.
+func (s *TestService) GradeAnswer(ctx context.Context, teacherID int, req *models.GradeAnswerRequest) (*models.TestAttempt, error) {
+	answer, needsReview, err := s.Repo.GetAnswerForReview(ctx, req.AnswerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCourseTeacher(ctx, teacherID, answer.CourseID); err != nil {
+		return nil, err
+	}
+
+	if !needsReview {
+		return nil, fmt.Errorf("%w: answer %d is already graded", ErrConflict, req.AnswerID)
+	}
+
+	attempt, err := s.Repo.GetAttemptByID(ctx, answer.AttemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status == "in_progress" {
+		return nil, fmt.Errorf("%w: attempt %d is still in progress", ErrConflict, answer.AttemptID)
+	}
+
+	if req.Points < 0 || req.Points > answer.MaxPoints {
+		return nil, fmt.Errorf("%w: points must be between 0 and %d", ErrInvalidInput, answer.MaxPoints)
+	}
+
+	saved, err := s.Repo.SaveReview(ctx, req.AnswerID, req.Points, req.Comment, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, fmt.Errorf("%w: answer %d is already graded", ErrConflict, req.AnswerID)
+	}
+
+	pending, err := s.Repo.CountPendingAnswers(ctx, answer.AttemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending == 0 {
+		// Просроченная попытка остается просроченной, меняется только балл
+		status := "completed"
+		if attempt.Status == "expired" {
+			status = attempt.Status
+		}
+
+		score, maxScore, err := s.calculateAttemptScore(ctx, answer.AttemptID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Repo.UpdateAttemptScore(ctx, answer.AttemptID, score, maxScore, scorePercentage(score, maxScore), status); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Repo.GetAttemptByID(ctx, answer.AttemptID)
+}
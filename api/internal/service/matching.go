@@ -0,0 +1,86 @@
+package service
+
+import (
+	"api/internal/models"
+	"math/rand"
+)
+
+// Режимы начисления баллов за вопрос
+const (
+	scoringAllOrNothing = "all_or_nothing"
+	scoringPartial      = "partial"
+)
+
+// matchingColumns формирует колонки для студента: левая идет в порядке position,
+// правая перемешивается генератором попытки и идентифицируется по right_key, а не по id пары
+func matchingColumns(pairs []models.MatchingPair, rng *rand.Rand) ([]models.MatchingItem, []models.MatchingItem) {
+	left := make([]models.MatchingItem, 0, len(pairs))
+	right := make([]models.MatchingItem, 0, len(pairs))
+	for _, p := range pairs {
+		left = append(left, models.MatchingItem{ID: p.ID, Text: p.LeftText})
+		right = append(right, models.MatchingItem{ID: p.RightKey, Text: p.RightText})
+	}
+
+	rng.Shuffle(len(right), func(i, j int) {
+		right[i], right[j] = right[j], right[i]
+	})
+
+	return left, right
+}
+
+// gradeMatching начисляет баллы за сопоставление. Пара засчитывается, если
+// выбранное правое значение совпадает по тексту с ожидаемым (так одинаковые
+// правые значения взаимозаменяемы). В режиме partial баллы начисляются
+// пропорционально верным парам, иначе — только за полностью верный ответ.
+func gradeMatching(question *models.Question, pairs []models.MatchingPair, answer models.MatchingAnswer) int {
+	if len(pairs) == 0 {
+		return 0
+	}
+
+	rightText := make(map[int]string, len(pairs))
+	for _, p := range pairs {
+		rightText[p.RightKey] = p.RightText
+	}
+
+	// Для каждого левого значения учитываем только первый выбор
+	chosen := make(map[int]int, len(answer.Matches))
+	for _, m := range answer.Matches {
+		if _, ok := chosen[m.LeftID]; !ok {
+			chosen[m.LeftID] = m.RightID
+		}
+	}
+
+	correct := 0
+	for _, p := range pairs {
+		key, ok := chosen[p.ID]
+		if !ok {
+			continue
+		}
+		if text, ok := rightText[key]; ok && text == p.RightText {
+			correct++
+		}
+	}
+
+	if question.ScoringMode == scoringPartial {
+		return (question.Points * correct) / len(pairs)
+	}
+	if correct == len(pairs) {
+		return question.Points
+	}
+	return 0
+}
+
+// newRightKeys возвращает n различных случайных ключей для правой колонки
+func newRightKeys(n int) []int {
+	keys := make([]int, 0, n)
+	used := make(map[int]bool, n)
+	for len(keys) < n {
+		k := rand.Intn(1<<30) + 1
+		if used[k] {
+			continue
+		}
+		used[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"api/internal/models"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// drawAttemptQuestions выбирает вопросы для новой попытки: вопросы вне разделов
+// входят всегда, из пула каждого раздела берется draw_count случайных.
+// Результат упорядочен по position, как в тесте.
+func drawAttemptQuestions(questions []models.Question, sections []models.TestSection, rng *rand.Rand) []models.Question {
+	pools := make(map[int][]models.Question, len(sections))
+	drawn := make([]models.Question, 0, len(questions))
+	for _, q := range questions {
+		if q.SectionID == nil {
+			drawn = append(drawn, q)
+			continue
+		}
+		pools[*q.SectionID] = append(pools[*q.SectionID], q)
+	}
+
+	for _, sec := range sections {
+		pool := pools[sec.ID]
+		rng.Shuffle(len(pool), func(i, j int) {
+			pool[i], pool[j] = pool[j], pool[i]
+		})
+		if sec.DrawCount < len(pool) {
+			pool = pool[:sec.DrawCount]
+		}
+		drawn = append(drawn, pool...)
+	}
+
+	sort.SliceStable(drawn, func(i, j int) bool {
+		if drawn[i].Position != drawn[j].Position {
+			return drawn[i].Position < drawn[j].Position
+		}
+		return drawn[i].ID < drawn[j].ID
+	})
+
+	return drawn
+}
+
+// attemptQuestions возвращает вопросы попытки. Попытки, начатые до появления
+// пулов, не имеют закрепленного набора и получают все вопросы теста.
+func (s *TestService) attemptQuestions(ctx context.Context, attempt *models.TestAttempt) ([]models.Question, error) {
+	questions, err := s.Repo.GetAttemptQuestions(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) > 0 {
+		return questions, nil
+	}
+
+	return s.Repo.GetTestQuestions(ctx, strconv.Itoa(attempt.TestID))
+}
+
+// validateSections проверяет, что ссылки вопросов на разделы корректны и
+// в каждом пуле достаточно вопросов для выборки
+func validateSections(req *models.CreateTestRequest) error {
+	poolSize := make([]int, len(req.Sections))
+	for _, q := range req.Questions {
+		if q.Section < 0 || q.Section > len(req.Sections) {
+			return fmt.Errorf("%w: question %q refers to unknown section %d", ErrInvalidInput, q.QuestionText, q.Section)
+		}
+		if q.Section > 0 {
+			poolSize[q.Section-1]++
+		}
+	}
+
+	for i, sec := range req.Sections {
+		if sec.DrawCount < 1 {
+			return fmt.Errorf("%w: section %d must draw at least one question", ErrInvalidInput, i+1)
+		}
+		if poolSize[i] < sec.DrawCount {
+			return fmt.Errorf("%w: section %d draws %d questions but has only %d", ErrInvalidInput, i+1, sec.DrawCount, poolSize[i])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,696 @@
+package service
+
+import (
+	"api/internal/exercise"
+	"api/internal/models"
+	"api/internal/notation"
+	"api/internal/relation"
+	"api/internal/render"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Режимы задания вопроса об отношении
+const (
+	relationModePairs      = "pairs"
+	relationModeProperties = "properties"
+	relationModeGenerated  = "generated"
+	relationModePartition  = "partition"
+)
+
+const (
+	// Наибольшее число элементов множества в вопросе: матрица ответа
+	// должна помещаться на странице теста
+	maxRelationElements = 12
+	// До такого размера множества условия проверяются на выполнимость перебором
+	// всех отношений (2^16 отношений для четырех элементов)
+	maxRelationBruteForce = 4
+)
+
+// relationCondition — свойство или вид отношения из условия вопроса
+type relationCondition func(*relation.Relation) bool
+
+// parseRelationCondition принимает название свойства (reflexive, symmetric, ...)
+// или вида отношения (equivalence, partial_order, ...)
+func parseRelationCondition(name string) (relationCondition, error) {
+	if p, err := relation.ParseProperty(name); err == nil {
+		return func(r *relation.Relation) bool { return r.Satisfies(p) }, nil
+	}
+	if c, err := relation.ParseClass(name); err == nil {
+		return func(r *relation.Relation) bool { return r.Is(c) }, nil
+	}
+	return nil, fmt.Errorf("unknown relation property or class %q", name)
+}
+
+// normalizeRelationSpec убирает пробелы вокруг элементов и названий условий.
+// Для сгенерированных упражнений элементы определяются размером множества.
+func normalizeRelationSpec(spec *models.RelationSpec) {
+	if spec.Mode == relationModeGenerated {
+		spec.Exercise = strings.ToLower(strings.TrimSpace(spec.Exercise))
+		if spec.Size == 0 {
+			spec.Size = exercise.DefaultSize
+		}
+		if len(spec.Elements) == 0 && spec.Size >= exercise.MinSize && spec.Size <= exercise.MaxSize {
+			spec.Elements = exercise.Elements(spec.Size)
+		}
+	}
+	for i := range spec.Elements {
+		spec.Elements[i] = strings.TrimSpace(spec.Elements[i])
+	}
+	for i := range spec.Pairs {
+		spec.Pairs[i].From = strings.TrimSpace(spec.Pairs[i].From)
+		spec.Pairs[i].To = strings.TrimSpace(spec.Pairs[i].To)
+	}
+	for _, names := range [][]string{spec.Required, spec.Forbidden} {
+		for i := range names {
+			names[i] = strings.ToLower(strings.TrimSpace(names[i]))
+		}
+	}
+}
+
+// validateRelationSpec проверяет задание вопроса об отношении при создании вопроса.
+// Условия режима properties на небольших множествах проверяются на выполнимость,
+// чтобы не сохранить вопрос, на который нельзя ответить верно.
+func validateRelationSpec(spec *models.RelationSpec) error {
+	if spec == nil {
+		return errors.New("relation questions require a relation spec")
+	}
+	if spec.Mode == relationModeGenerated {
+		return validateGeneratedSpec(spec)
+	}
+
+	set, err := relation.NewSet(spec.Elements...)
+	if err != nil {
+		return err
+	}
+	if set.Len() == 0 || set.Len() > maxRelationElements {
+		return fmt.Errorf("relation questions require from 1 to %d elements", maxRelationElements)
+	}
+
+	switch spec.Mode {
+	case relationModePairs:
+		if len(spec.Required) > 0 || len(spec.Forbidden) > 0 {
+			return errors.New("relation questions in pairs mode do not use property conditions")
+		}
+		_, err := relation.FromPairs(set, toRelationPairs(spec.Pairs))
+		return err
+
+	case relationModePartition:
+		if len(spec.Required) > 0 || len(spec.Forbidden) > 0 {
+			return errors.New("relation questions in partition mode do not use property conditions")
+		}
+		r, err := relation.FromPairs(set, toRelationPairs(spec.Pairs))
+		if err != nil {
+			return err
+		}
+		if !r.IsEquivalence() {
+			return errors.New("relation questions in partition mode require an equivalence relation")
+		}
+		return nil
+
+	case relationModeProperties:
+		if len(spec.Required)+len(spec.Forbidden) == 0 {
+			return errors.New("relation questions in properties mode require at least one condition")
+		}
+		required, forbidden, err := relationConditions(spec)
+		if err != nil {
+			return err
+		}
+		for _, name := range spec.Required {
+			for _, other := range spec.Forbidden {
+				if name == other {
+					return fmt.Errorf("condition %q is both required and forbidden", name)
+				}
+			}
+		}
+		if set.Len() <= maxRelationBruteForce && !relationSatisfiable(set, required, forbidden) {
+			return fmt.Errorf("no relation on %s satisfies the conditions", set)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown relation question mode %s", spec.Mode)
+	}
+}
+
+// validateGeneratedSpec проверяет вид и размер упражнения. Отношение и ключ
+// строятся для каждой попытки, поэтому пары и условия в задании не нужны.
+func validateGeneratedSpec(spec *models.RelationSpec) error {
+	if _, err := exercise.ParseKind(spec.Exercise); err != nil {
+		return err
+	}
+	if spec.Size < exercise.MinSize || spec.Size > exercise.MaxSize {
+		return exercise.ErrInvalidSize
+	}
+	if len(spec.Pairs)+len(spec.Required)+len(spec.Forbidden) > 0 {
+		return errors.New("generated relation questions do not use pairs or property conditions")
+	}
+	if elements := exercise.Elements(spec.Size); !slices.Equal(spec.Elements, elements) {
+		return fmt.Errorf("generated relation questions use elements %s", strings.Join(elements, ", "))
+	}
+	return nil
+}
+
+// relationVariant строит упражнение попытки. Зерно выводится из зерна попытки
+// и номера вопроса, поэтому вариант не хранится в БД и совпадает при показе
+// вопроса и при проверке ответа.
+func relationVariant(spec *models.RelationSpec, attempt *models.TestAttempt, questionID int) (*exercise.Exercise, error) {
+	kind, err := exercise.ParseKind(spec.Exercise)
+	if err != nil {
+		return nil, err
+	}
+	return exercise.Generate(kind, spec.Size, attemptRand(attempt.ShuffleSeed, questionID).Int63())
+}
+
+func relationConditions(spec *models.RelationSpec) ([]relationCondition, []relationCondition, error) {
+	parse := func(names []string) ([]relationCondition, error) {
+		conditions := make([]relationCondition, 0, len(names))
+		for _, name := range names {
+			c, err := parseRelationCondition(name)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, c)
+		}
+		return conditions, nil
+	}
+
+	required, err := parse(spec.Required)
+	if err != nil {
+		return nil, nil, err
+	}
+	forbidden, err := parse(spec.Forbidden)
+	if err != nil {
+		return nil, nil, err
+	}
+	return required, forbidden, nil
+}
+
+// relationSatisfiable перебирает все отношения на множестве в поисках подходящего
+func relationSatisfiable(set *relation.Set, required, forbidden []relationCondition) bool {
+	n := set.Len()
+	for mask := 0; mask < 1<<(n*n); mask++ {
+		matrix := make([][]bool, n)
+		for i := range matrix {
+			matrix[i] = make([]bool, n)
+			for j := range matrix[i] {
+				matrix[i][j] = mask&(1<<(i*n+j)) != 0
+			}
+		}
+		r, _ := relation.FromMatrix(set, matrix)
+		if relationConditionsMet(r, required, forbidden) == len(required)+len(forbidden) {
+			return true
+		}
+	}
+	return false
+}
+
+// relationConditionsMet возвращает число выполненных условий
+func relationConditionsMet(r *relation.Relation, required, forbidden []relationCondition) int {
+	met := 0
+	for _, holds := range required {
+		if holds(r) {
+			met++
+		}
+	}
+	for _, holds := range forbidden {
+		if !holds(r) {
+			met++
+		}
+	}
+	return met
+}
+
+// relationStudentView заполняет вопрос об отношении для студента: элементы
+// множества, форму ответа и условие без ключа — отношение, которое нужно
+// разбить на классы, или свой вариант сгенерированного упражнения
+func relationStudentView(view *models.StudentQuestion, spec *models.RelationSpec, attempt *models.TestAttempt) error {
+	view.Elements = spec.Elements
+	view.AnswerForm = exercise.AnswerPairs
+
+	switch spec.Mode {
+	case relationModePartition:
+		set, err := relation.NewSet(spec.Elements...)
+		if err != nil {
+			return err
+		}
+		view.Relation, err = relation.FromPairs(set, toRelationPairs(spec.Pairs))
+		if err != nil {
+			return err
+		}
+		view.AnswerForm = exercise.AnswerClasses
+
+	case relationModeGenerated:
+		variant, err := relationVariant(spec, attempt, view.ID)
+		if err != nil {
+			return err
+		}
+		view.Exercise = &variant.Task
+		view.AnswerForm = variant.Answer
+	}
+	return nil
+}
+
+// gradeRelation начисляет баллы за отношение, заданное студентом.
+// В режиме pairs при partial баллы пропорциональны доле общих пар среди
+// всех пар ответа и ключа, так что штрафуются и пропущенные, и лишние пары;
+// в режиме properties — доле выполненных условий, в режиме partition — доле
+// верных классов среди классов ответа и ключа, в режиме generated — оценке
+// упражнения попытки. Иначе баллы даются только за полностью верный ответ.
+func gradeRelation(question *models.Question, spec *models.RelationSpec, answer models.RelationAnswer, attempt *models.TestAttempt) (int, error) {
+	set, err := relation.NewSet(spec.Elements...)
+	if err != nil {
+		return 0, err
+	}
+
+	given, err := answerRelation(set, answer)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	var correct, total int
+	switch spec.Mode {
+	case relationModePairs:
+		expected, err := relation.FromPairs(set, toRelationPairs(spec.Pairs))
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range expected.Pairs() {
+			if given.Has(p.From, p.To) {
+				correct++
+			}
+		}
+		total = expected.Len() + given.Len() - correct
+
+	case relationModeProperties:
+		required, forbidden, err := relationConditions(spec)
+		if err != nil {
+			return 0, err
+		}
+		correct = relationConditionsMet(given, required, forbidden)
+		total = len(required) + len(forbidden)
+
+	case relationModePartition:
+		key, err := partitionKey(set, spec)
+		if err != nil {
+			return 0, err
+		}
+		classes, err := relation.NormalizePartition(set, answer.Classes)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		correct = relation.CommonBlocks(key, classes)
+		total = max(len(key), len(classes))
+
+	case relationModeGenerated:
+		ex, err := relationVariant(spec, attempt, question.ID)
+		if err != nil {
+			return 0, err
+		}
+		correct, total, err = ex.Score(exercise.Response{
+			Relation:   given,
+			Properties: answer.Properties,
+			Classes:    answer.Classes,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+	default:
+		return 0, fmt.Errorf("unknown relation question mode %s", spec.Mode)
+	}
+
+	if correct == total {
+		return question.Points, nil
+	}
+	if question.ScoringMode == scoringPartial {
+		return (question.Points * correct) / total, nil
+	}
+	return 0, nil
+}
+
+// relationFeedback разбирает ответ на вопрос об отношении: какие пары ключа
+// пропущены и какие лишние, или какие условия нарушены и почему. В упражнении
+// на свойства объясняется каждое неверно определенное свойство.
+// Для верного ответа разбор не нужен и возвращается nil.
+func (s *TestService) relationFeedback(ctx context.Context, attempt *models.TestAttempt, questionID int, answerData json.RawMessage) (*models.RelationFeedback, error) {
+	spec, err := s.Repo.GetRelationSpec(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	var answer models.RelationAnswer
+	if err := json.Unmarshal(answerData, &answer); err != nil {
+		return nil, err
+	}
+
+	set, err := relation.NewSet(spec.Elements...)
+	if err != nil {
+		return nil, err
+	}
+	given, err := answerRelation(set, answer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	feedback := &models.RelationFeedback{}
+	switch spec.Mode {
+	case relationModePairs:
+		expected, err := relation.FromPairs(set, toRelationPairs(spec.Pairs))
+		if err != nil {
+			return nil, err
+		}
+		feedback.Missing, feedback.Extra = pairDifference(expected, given)
+
+	case relationModePartition:
+		// Разбиение сравнивается через свою эквивалентность: пропущенная пара
+		// означает элементы, которые должны быть в одном классе, лишняя — в разных
+		expected, err := relation.FromPairs(set, toRelationPairs(spec.Pairs))
+		if err != nil {
+			return nil, err
+		}
+		partition, err := relation.FromPartition(set, answer.Classes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		feedback.Missing, feedback.Extra = pairDifference(expected, partition)
+
+	case relationModeProperties:
+		for _, name := range spec.Required {
+			ex, err := given.Explain(name)
+			if err != nil {
+				return nil, err
+			}
+			if !ex.Holds {
+				feedback.Violations = append(feedback.Violations, ex)
+			}
+		}
+		for _, name := range spec.Forbidden {
+			ex, err := given.Explain(name)
+			if err != nil {
+				return nil, err
+			}
+			if ex.Holds {
+				ex.Text += ". По условию это не должно выполняться"
+				feedback.Violations = append(feedback.Violations, ex)
+			}
+		}
+
+	case relationModeGenerated:
+		ex, err := relationVariant(spec, attempt, questionID)
+		if err != nil {
+			return nil, err
+		}
+		switch ex.Answer {
+		case exercise.AnswerPairs:
+			feedback.Missing, feedback.Extra = pairDifference(ex.Key.Relation, given)
+		case exercise.AnswerProperties:
+			r := ex.Relations[0].Relation
+			for _, p := range ex.Misjudged(answer.Properties) {
+				feedback.Violations = append(feedback.Violations, r.ExplainProperty(p))
+			}
+		case exercise.AnswerClasses:
+			partition, err := relation.FromPartition(set, answer.Classes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+			}
+			feedback.Missing, feedback.Extra = pairDifference(ex.Relations[0].Relation, partition)
+		}
+	}
+
+	if len(feedback.Missing)+len(feedback.Extra)+len(feedback.Violations) == 0 {
+		return nil, nil
+	}
+	return feedback, nil
+}
+
+// partitionKey возвращает классы эквивалентности отношения из задания
+func partitionKey(set *relation.Set, spec *models.RelationSpec) ([][]string, error) {
+	r, err := relation.FromPairs(set, toRelationPairs(spec.Pairs))
+	if err != nil {
+		return nil, err
+	}
+	return r.Quotient()
+}
+
+// pairDifference возвращает пары ключа, которых нет в ответе, и лишние пары ответа
+func pairDifference(expected, given *relation.Relation) ([]models.RelationPair, []models.RelationPair) {
+	var missing, extra []models.RelationPair
+	for _, p := range expected.Pairs() {
+		if !given.Has(p.From, p.To) {
+			missing = append(missing, models.RelationPair{From: p.From, To: p.To})
+		}
+	}
+	for _, p := range given.Pairs() {
+		if !expected.Has(p.From, p.To) {
+			extra = append(extra, models.RelationPair{From: p.From, To: p.To})
+		}
+	}
+	return missing, extra
+}
+
+// GenerateExercise строит упражнение для предпросмотра преподавателем:
+// задание вместе с ключом ответа. Без зерна выбирается случайное.
+func (s *TestService) GenerateExercise(req *models.GenerateExerciseRequest) (*exercise.Exercise, error) {
+	kind, err := exercise.ParseKind(strings.ToLower(strings.TrimSpace(req.Kind)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	size := req.Size
+	if size == 0 {
+		size = exercise.DefaultSize
+	}
+	seed := req.Seed
+	if seed == 0 {
+		seed = newShuffleSeed()
+	}
+
+	ex, err := exercise.Generate(kind, size, seed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return ex, nil
+}
+
+// EquivalenceClasses находит классы эквивалентности отношения для тренажера
+// или, если передано разбиение, строит по нему отношение эквивалентности
+func (s *TestService) EquivalenceClasses(req *models.EquivalenceRequest) (*models.EquivalenceResponse, error) {
+	set, err := relation.NewSet(req.Elements...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if set.Len() == 0 || set.Len() > maxRelationElements {
+		return nil, fmt.Errorf("%w: relation must have from 1 to %d elements", ErrInvalidInput, maxRelationElements)
+	}
+
+	var r *relation.Relation
+	if req.Partition != nil {
+		r, err = relation.FromPartition(set, req.Partition)
+	} else {
+		r, err = answerRelation(set, models.RelationAnswer{Pairs: req.Pairs, Matrix: req.Matrix})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	response := &models.EquivalenceResponse{Relation: r, IsEquivalence: r.IsEquivalence()}
+	if !response.IsEquivalence {
+		explanation := r.ExplainClass(relation.Equivalence)
+		response.Explanation = &explanation
+		return response, nil
+	}
+	response.Classes, _ = r.Quotient()
+	return response, nil
+}
+
+// AnalyzePoset проверяет, что отношение — частичный порядок, и находит для
+// тренажера диаграмму Хассе, экстремальные элементы, грани подмножества
+// и пару элементов, из-за которой порядок не является решеткой
+func (s *TestService) AnalyzePoset(req *models.PosetRequest) (*models.PosetResponse, error) {
+	set, err := relation.NewSet(req.Elements...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if set.Len() == 0 || set.Len() > maxRelationElements {
+		return nil, fmt.Errorf("%w: relation must have from 1 to %d elements", ErrInvalidInput, maxRelationElements)
+	}
+	r, err := answerRelation(set, models.RelationAnswer{Pairs: req.Pairs, Matrix: req.Matrix})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	response := &models.PosetResponse{Relation: r, IsPartialOrder: r.IsPartialOrder()}
+	if !response.IsPartialOrder {
+		explanation := r.ExplainClass(relation.PartialOrder)
+		response.Explanation = &explanation
+		return response, nil
+	}
+
+	response.Covering, _ = r.Covering()
+	response.Minimal = r.MinimalElements()
+	response.Maximal = r.MaximalElements()
+	if least, ok := r.Least(); ok {
+		response.Least = &least
+	}
+	if greatest, ok := r.Greatest(); ok {
+		response.Greatest = &greatest
+	}
+
+	pair, missing, found := r.LatticeViolation()
+	response.IsLattice = !found
+	if found {
+		bound := "точной верхней грани (супремума)"
+		if missing == "infimum" {
+			bound = "точной нижней грани (инфимума)"
+		}
+		response.LatticeViolation = &models.LatticeViolation{
+			Pair:    pair,
+			Missing: missing,
+			Text:    fmt.Sprintf("Порядок не является решеткой: у элементов %s и %s нет %s", pair.From, pair.To, bound),
+		}
+	}
+
+	if len(req.Subset) > 0 {
+		response.Subset, err = subsetBounds(r, req.Subset)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+	}
+	return response, nil
+}
+
+func subsetBounds(r *relation.Relation, subset []string) (*models.SubsetBounds, error) {
+	for i := range subset {
+		subset[i] = strings.TrimSpace(subset[i])
+	}
+
+	bounds := &models.SubsetBounds{Elements: subset}
+	var err error
+	if bounds.Upper, err = r.UpperBounds(subset); err != nil {
+		return nil, err
+	}
+	if bounds.Lower, err = r.LowerBounds(subset); err != nil {
+		return nil, err
+	}
+	if sup, ok, _ := r.Supremum(subset); ok {
+		bounds.Supremum = &sup
+	}
+	if inf, ok, _ := r.Infimum(subset); ok {
+		bounds.Infimum = &inf
+	}
+	return bounds, nil
+}
+
+// ParseRelation разбирает текстовую запись отношения для импорта в тренажер.
+// Ошибка записи возвращается со строкой и столбцом, где остановился разбор.
+func (s *TestService) ParseRelation(req *models.ParseRelationRequest) (*models.ParseRelationResponse, error) {
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, fmt.Errorf("%w: relation text is required", ErrInvalidInput)
+	}
+	result, err := notation.Parse(req.Text, req.Elements)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if n := result.Relation.Set().Len(); n == 0 || n > maxRelationElements {
+		return nil, fmt.Errorf("%w: relation must have from 1 to %d elements", ErrInvalidInput, maxRelationElements)
+	}
+	return &models.ParseRelationResponse{Relation: result.Relation, Format: string(result.Format)}, nil
+}
+
+// RenderRelation рисует отношение в SVG и возвращает изображение с ключом
+// канонического вида отношения. Изображение берется из кэша, если такое
+// отношение уже рисовалось.
+func (s *TestService) RenderRelation(req *models.RenderRelationRequest) ([]byte, string, error) {
+	view, err := render.ParseView(req.View)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	set, err := relation.NewSet(req.Elements...)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if set.Len() == 0 || set.Len() > maxRelationElements {
+		return nil, "", fmt.Errorf("%w: relation must have from 1 to %d elements", ErrInvalidInput, maxRelationElements)
+	}
+	r, err := answerRelation(set, models.RelationAnswer{Matrix: req.Matrix})
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	key := render.Key(view, r)
+	if svg, ok := s.images.Get(key); ok {
+		return svg, key, nil
+	}
+	svg, err := render.Render(view, r)
+	if err != nil {
+		// Диаграмма Хассе есть только у частичного порядка
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	s.images.Put(key, svg)
+	return svg, key, nil
+}
+
+// ExplainRelation проверяет свойства и виды отношения для тренажера
+// и объясняет каждый результат, приводя контрпример при нарушении
+func (s *TestService) ExplainRelation(req *models.ExplainRelationRequest) (*models.ExplainRelationResponse, error) {
+	set, err := relation.NewSet(req.Elements...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if set.Len() == 0 || set.Len() > maxRelationElements {
+		return nil, fmt.Errorf("%w: relation must have from 1 to %d elements", ErrInvalidInput, maxRelationElements)
+	}
+
+	r, err := answerRelation(set, models.RelationAnswer{Pairs: req.Pairs, Matrix: req.Matrix})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	response := &models.ExplainRelationResponse{Relation: r}
+	if req.Condition == "" {
+		response.Explanations = r.ExplainAll()
+		return response, nil
+	}
+
+	ex, err := r.Explain(strings.ToLower(strings.TrimSpace(req.Condition)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	response.Explanations = []relation.Explanation{ex}
+	return response, nil
+}
+
+// answerRelation строит отношение из ответа: по тексту, если он передан,
+// затем по матрице, иначе по парам
+func answerRelation(set *relation.Set, answer models.RelationAnswer) (*relation.Relation, error) {
+	if strings.TrimSpace(answer.Text) != "" {
+		result, err := notation.Parse(answer.Text, set.Elements())
+		if err != nil {
+			return nil, err
+		}
+		return result.Relation, nil
+	}
+	if answer.Matrix == nil {
+		return relation.FromPairs(set, toRelationPairs(answer.Pairs))
+	}
+
+	matrix := make([][]bool, len(answer.Matrix))
+	for i, row := range answer.Matrix {
+		matrix[i] = make([]bool, len(row))
+		for j, v := range row {
+			matrix[i][j] = v != 0
+		}
+	}
+	return relation.FromMatrix(set, matrix)
+}
+
+func toRelationPairs(pairs []models.RelationPair) []relation.Pair {
+	result := make([]relation.Pair, len(pairs))
+	for i, p := range pairs {
+		result[i] = relation.Pair{From: strings.TrimSpace(p.From), To: strings.TrimSpace(p.To)}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"api/internal/models"
+	"context"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// newShuffleSeed возвращает зерно перестановки для новой попытки
+func newShuffleSeed() int64 {
+	return rand.Int63()
+}
+
+// attemptRand возвращает генератор, детерминированный для пары (попытка, вопрос).
+// Так порядок вариантов одного вопроса не зависит от того, какие вопросы были
+// загружены до него.
+func attemptRand(seed int64, questionID int) *rand.Rand {
+	return rand.New(rand.NewSource(seed + int64(questionID)*1000003))
+}
+
+// shuffleStudentQuestions переставляет вопросы по зерну попытки и перенумеровывает
+// position, чтобы клиенты, сортирующие по position, показали тот же порядок
+func shuffleStudentQuestions(questions []models.StudentQuestion, seed int64) {
+	shuffleQuestionOrder(questions, seed)
+	for i := range questions {
+		questions[i].Position = i + 1
+	}
+}
+
+// shuffleQuestionOrder переставляет вопросы попытки (или их id) по ее зерну.
+// Перестановка зависит только от зерна и числа вопросов, поэтому ответы
+// попытки можно расставить в том же порядке, в каком студент видел вопросы.
+func shuffleQuestionOrder[T any](items []T, seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+}
+
+// shuffleStudentOptions переставляет варианты ответа вопроса по зерну попытки
+func shuffleStudentOptions(options []models.StudentOption, seed int64, questionID int) {
+	rng := attemptRand(seed, questionID)
+	rng.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	for i := range options {
+		options[i].Position = i + 1
+	}
+}
+
+// shownQuestionOrder возвращает номера вопросов попытки в том порядке, в котором
+// их видел студент: закрепленный порядок выдачи, перемешанный так же, как в
+// GetStudentTest, если тест перемешивает вопросы. test может быть nil.
+func (s *TestService) shownQuestionOrder(ctx context.Context, attempt *models.TestAttempt, test *models.Test) (map[int]int, error) {
+	if test == nil {
+		var err error
+		if test, err = s.Repo.GetTestByID(ctx, strconv.Itoa(attempt.TestID)); err != nil {
+			return nil, err
+		}
+	}
+	questions, err := s.attemptQuestions(ctx, attempt)
+	if err != nil {
+		return nil, err
+	}
+	if test.ShuffleQuestions {
+		shuffleQuestionOrder(questions, attempt.ShuffleSeed)
+	}
+
+	order := make(map[int]int, len(questions))
+	for i, q := range questions {
+		order[q.ID] = i
+	}
+	return order, nil
+}
+
+// sortByQuestionOrder упорядочивает ответы по номерам вопросов из shownQuestionOrder;
+// ответы на вопросы вне попытки остаются в конце
+func sortByQuestionOrder[T any](answers []T, order map[int]int, questionID func(T) int) {
+	position := func(a T) int {
+		if i, ok := order[questionID(a)]; ok {
+			return i
+		}
+		return len(order)
+	}
+	sort.SliceStable(answers, func(i, j int) bool {
+		return position(answers[i]) < position(answers[j])
+	})
+}
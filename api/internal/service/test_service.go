@@ -2,71 +2,293 @@ package service
 
 import (
 	"api/internal/models"
+	"api/internal/render"
 	"api/internal/repository"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"math"
+	"math/rand"
 	"strconv"
 	"time"
 )
 
 type TestService struct {
 	Repo *repository.TestRepository
+	// Готовые SVG-изображения отношений по каноническому виду
+	images *render.Cache
 }
 
+// Сколько изображений отношений держать в памяти
+const imageCacheSize = 512
+
 func NewTestService(repo *repository.TestRepository) *TestService {
-	return &TestService{Repo: repo}
+	return &TestService{Repo: repo, images: render.NewCache(imageCacheSize)}
 }
 
-func (s *TestService) GetTest(ctx context.Context, testID string) (*models.Test, []models.Question, error) {
+// GetStudentTest возвращает тест без ключа ответов. Доступен студенту, пока у него
+// идет попытка, или после того, как преподаватель открыл результаты.
+func (s *TestService) GetStudentTest(ctx context.Context, userID int, testID string) (*models.Test, []models.StudentQuestion, error) {
 	test, err := s.Repo.GetTestByID(ctx, testID)
 	if err != nil {
-		log.Println("Ошибка получения теста по ID теста(файл test_service метод GetTest) " + err.Error())
+		log.Println("Ошибка получения теста по ID теста(файл test_service метод GetStudentTest) " + err.Error())
 		return nil, nil, err
 	}
 
-	questions, err := s.Repo.GetTestQuestions(ctx, testID)
+	attempt, err := s.studentTestAttempt(ctx, userID, test)
 	if err != nil {
-		log.Println("Ошибка получения вопросов по ID теста(файл test_service метод GetTest) " + err.Error())
 		return nil, nil, err
 	}
 
-	// Загружаем варианты ответов для каждого вопроса
-	for i := range questions {
-		if questions[i].QuestionType == "single_choice" || questions[i].QuestionType == "multiple_choice" {
-			options, err := s.Repo.GetQuestionOptions(ctx, questions[i].ID)
+	questions, err := s.attemptQuestions(ctx, attempt)
+	if err != nil {
+		log.Println("Ошибка получения вопросов попытки(файл test_service метод GetStudentTest) " + err.Error())
+		return nil, nil, err
+	}
+
+	views := make([]models.StudentQuestion, 0, len(questions))
+	for _, q := range questions {
+		view := models.StudentQuestion{
+			ID:           q.ID,
+			TestID:       q.TestID,
+			QuestionText: q.QuestionText,
+			QuestionType: q.QuestionType,
+			Points:       q.Points,
+			Position:     q.Position,
+			Options:      []models.StudentOption{},
+		}
+
+		// Загружаем варианты ответов для каждого вопроса
+		switch q.QuestionType {
+		case "single_choice", "multiple_choice":
+			options, err := s.Repo.GetQuestionOptions(ctx, q.ID)
+			if err != nil {
+				log.Println("Ошибка получения вариантов ответа по ID вопроса(файл test_service метод GetStudentTest) " + err.Error())
+				return nil, nil, err
+			}
+			for _, opt := range options {
+				view.Options = append(view.Options, models.StudentOption{
+					ID:         opt.ID,
+					QuestionID: opt.QuestionID,
+					OptionText: opt.OptionText,
+					Position:   opt.Position,
+				})
+			}
+			if test.ShuffleOptions {
+				shuffleStudentOptions(view.Options, attempt.ShuffleSeed, q.ID)
+			}
+		case "matching":
+			pairs, err := s.Repo.GetMatchingPairs(ctx, q.ID)
+			if err != nil {
+				log.Println("Ошибка получения пар сопоставления по ID вопроса(файл test_service метод GetStudentTest) " + err.Error())
+				return nil, nil, err
+			}
+			view.MatchingLeft, view.MatchingRight = matchingColumns(pairs, attemptRand(attempt.ShuffleSeed, q.ID))
+		case "relation":
+			spec, err := s.Repo.GetRelationSpec(ctx, q.ID)
 			if err != nil {
-				log.Println("Ошибка получения вариантов ответа по ID вопроса(файл test_service метод GetTest) " + err.Error())
+				log.Println("Ошибка получения задания вопроса об отношении(файл test_service метод GetStudentTest) " + err.Error())
 				return nil, nil, err
 			}
-			questions[i].Options = options
-		} else if questions[i].QuestionType == "text_answer" {
-			option := models.AnswerOption{
-				ID:         -1,
-				QuestionID: -1,
-				OptionText: "",
-				Position:   -1,
+			if err := relationStudentView(&view, spec, attempt); err != nil {
+				log.Println("Ошибка подготовки вопроса об отношении(файл test_service метод GetStudentTest) " + err.Error())
+				return nil, nil, err
 			}
-			questions[i].Options = []models.AnswerOption{option}
+		case "text_answer":
+			// Десктоп-клиент строит поле ввода по единственному пустому варианту
+			view.Options = []models.StudentOption{{ID: -1, QuestionID: -1, Position: -1}}
+		}
+
+		views = append(views, view)
+	}
+
+	if test.ShuffleQuestions {
+		shuffleStudentQuestions(views, attempt.ShuffleSeed)
+	}
+
+	return test, views, nil
+}
+
+// studentTestAttempt проверяет доступ студента к тесту и возвращает попытку,
+// порядок вопросов которой нужно показать: текущую или, после открытия
+// результатов, последнюю
+func (s *TestService) studentTestAttempt(ctx context.Context, userID int, test *models.Test) (*models.TestAttempt, error) {
+	active, err := s.Repo.GetActiveAttempt(ctx, userID, test.ID)
+	if err == nil && !attemptOverdue(active, time.Now()) {
+		return active, nil
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if test.ResultsReleased {
+		latest, err := s.Repo.GetLatestAttempt(ctx, userID, test.ID)
+		if err == nil {
+			return latest, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
 		}
 	}
 
-	return test, questions, nil
+	return nil, fmt.Errorf("%w: test %d is available only during an attempt", ErrForbidden, test.ID)
+}
+
+// GetTeacherTest возвращает тест с полным ключом ответов преподавателю курса
+func (s *TestService) GetTeacherTest(ctx context.Context, teacherID int, testID string) (*models.Test, []models.TeacherQuestion, error) {
+	test, err := s.Repo.GetTestByID(ctx, testID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkCourseTeacher(ctx, teacherID, test.CourseID); err != nil {
+		return nil, nil, err
+	}
+
+	test.Sections, err = s.Repo.GetTestSections(ctx, test.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	questions, err := s.Repo.GetTestQuestions(ctx, testID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	views := make([]models.TeacherQuestion, 0, len(questions))
+	for _, q := range questions {
+		view := models.TeacherQuestion{Question: q}
+		if err := s.loadAnswerKey(ctx, &view); err != nil {
+			return nil, nil, err
+		}
+
+		views = append(views, view)
+	}
+
+	return test, views, nil
+}
+
+// loadAnswerKey загружает ключ ответов вопроса в зависимости от его типа
+func (s *TestService) loadAnswerKey(ctx context.Context, view *models.TeacherQuestion) error {
+	var err error
+	switch view.QuestionType {
+	case "single_choice", "multiple_choice":
+		view.Options, err = s.Repo.GetQuestionAnswerKey(ctx, view.ID)
+	case "matching":
+		view.Pairs, err = s.Repo.GetMatchingPairs(ctx, view.ID)
+	case "text_answer":
+		view.AnswerRules, err = s.Repo.GetTextAnswerRules(ctx, view.ID)
+	case "relation":
+		view.Relation, err = s.Repo.GetRelationSpec(ctx, view.ID)
+	}
+	return err
+}
+
+// SetResultsReleased открывает или закрывает студентам просмотр теста после попыток
+func (s *TestService) SetResultsReleased(ctx context.Context, teacherID, testID int, released bool) error {
+	test, err := s.Repo.GetTestByID(ctx, strconv.Itoa(testID))
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCourseTeacher(ctx, teacherID, test.CourseID); err != nil {
+		return err
+	}
+
+	return s.Repo.SetResultsReleased(ctx, testID, released)
+}
+
+func (s *TestService) checkCourseTeacher(ctx context.Context, teacherID, courseID int) error {
+	ok, err := s.Repo.TeacherHasCourse(ctx, teacherID, courseID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: course %d is not taught by this teacher", ErrForbidden, courseID)
+	}
+	return nil
 }
 
 func (s *TestService) StartAttempt(ctx context.Context, userID, testID int) (*models.TestAttempt, error) {
-	// Проверяем, можно ли начать тест (например, не превышено ли max_attempts)
+	// Проверяем, можно ли начать тест
+	test, err := s.Repo.GetTestByID(ctx, strconv.Itoa(testID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !test.EndDate.IsZero() && now.After(test.EndDate) {
+		return nil, fmt.Errorf("%w: test %d closed at %s", ErrForbidden, testID, test.EndDate.Format(time.RFC3339))
+	}
+
+	// Незавершенная попытка продолжается, а не создается заново
+	active, err := s.Repo.GetActiveAttempt(ctx, userID, testID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if !attemptOverdue(active, now) {
+			return active, nil
+		}
+		// Просроченная попытка закрывается сразу, не дожидаясь фоновой проверки,
+		// чтобы у пользователя не оставалось двух незавершенных попыток
+		if err := s.expireAttempt(ctx, active.ID); err != nil {
+			return nil, fmt.Errorf("failed to expire attempt %d: %w", active.ID, err)
+		}
+	}
+
+	if test.Attempts > 0 {
+		used, err := s.Repo.CountUserAttempts(ctx, userID, testID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= test.Attempts {
+			return nil, fmt.Errorf("%w: attempt limit %d reached", ErrForbidden, test.Attempts)
+		}
+	}
 
 	attempt := &models.TestAttempt{
-		UserID:    userID,
-		TestID:    testID,
-		StartedAt: time.Now(),
-		Status:    "in_progress",
+		UserID:     userID,
+		TestID:     testID,
+		StartedAt:  now,
+		DeadlineAt: attemptDeadline(test, now),
+		Status:     "in_progress",
+		// Зерно сохраняется всегда: настройки перемешивания могут включить позже
+		ShuffleSeed: newShuffleSeed(),
+		TestVersion: test.Version,
+	}
+
+	// Набор вопросов выбирается один раз и закрепляется за попыткой
+	questions, err := s.Repo.GetTestQuestions(ctx, strconv.Itoa(testID))
+	if err != nil {
+		return nil, err
+	}
+	sections, err := s.Repo.GetTestSections(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	drawn := drawAttemptQuestions(questions, sections, rand.New(rand.NewSource(attempt.ShuffleSeed)))
+
+	tx, err := s.Repo.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txRepo := repository.NewTxRepository(tx)
+
+	if err := txRepo.CreateAttempt(ctx, attempt); err != nil {
+		return nil, err
+	}
+	for i, q := range drawn {
+		if err := txRepo.AddAttemptQuestion(ctx, attempt.ID, q.ID, i+1); err != nil {
+			return nil, fmt.Errorf("failed to store attempt questions: %w", err)
+		}
 	}
 
-	if err := s.Repo.CreateAttempt(ctx, attempt); err != nil {
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -75,34 +297,135 @@ func (s *TestService) StartAttempt(ctx context.Context, userID, testID int) (*mo
 
 func (s *TestService) SubmitAnswer(ctx context.Context, userID int, answer *models.UserAnswer) error {
 	// Проверяем, принадлежит ли attempt пользователю
+	attempt, err := s.ownedActiveAttempt(ctx, userID, answer.AttemptID)
+	if err != nil {
+		return err
+	}
+
+	if attemptOverdue(attempt, time.Now()) {
+		return fmt.Errorf("%w: attempt %d deadline passed", ErrConflict, attempt.ID)
+	}
 
-	// Получаем вопрос и правильные ответы
+	// Вопрос должен входить в набор, выпавший этой попытке
+	questions, err := s.attemptQuestions(ctx, attempt)
+	if err != nil {
+		return err
+	}
+	var question *models.Question
+	for i := range questions {
+		if questions[i].ID == answer.QuestionID {
+			question = &questions[i]
+			break
+		}
+	}
+	if question == nil {
+		return fmt.Errorf("%w: question %d is not part of attempt %d", ErrInvalidInput, answer.QuestionID, attempt.ID)
+	}
 
 	// В зависимости от типа вопроса проверяем ответ
-	points, err := s.evaluateAnswer(ctx, answer.QuestionID, answer.AnswerData)
+	points, needsReview, err := s.evaluateAnswer(ctx, attempt, question, answer.AnswerData)
 	if err != nil {
 		log.Println(err.Error())
 		return err
 	}
 
 	answer.PointsEarned = points
+	answer.NeedsReview = needsReview
+
+	// К ответу на вопрос об отношении прикладывается разбор ошибок
+	if question.QuestionType == "relation" {
+		answer.Feedback, err = s.relationFeedback(ctx, attempt, question.ID, answer.AnswerData)
+		if err != nil {
+			return err
+		}
+	}
+
 	return s.Repo.SaveAnswer(ctx, answer)
 }
 
-func (s *TestService) evaluateAnswer(ctx context.Context, questionID int, answerData json.RawMessage) (int, error) {
-	// Получаем вопрос и его тип
-	questions, err := s.Repo.GetTestQuestions(ctx, strconv.Itoa(questionID))
-	if err != nil || len(questions) == 0 {
-		return 0, fmt.Errorf("question not found")
+// GetAttemptProgress возвращает незавершенную попытку пользователя и уже сохраненные
+// ответы, чтобы веб-страница и десктоп-клиент могли продолжить после перезагрузки.
+// Если attemptID равен 0, берется текущая попытка по testID.
+func (s *TestService) GetAttemptProgress(ctx context.Context, userID, testID, attemptID int) (*models.AttemptProgress, error) {
+	if attemptID == 0 {
+		active, err := s.Repo.GetActiveAttempt(ctx, userID, testID)
+		if err != nil {
+			return nil, err
+		}
+		attemptID = active.ID
+	}
+
+	attempt, err := s.ownedActiveAttempt(ctx, userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if testID != 0 && attempt.TestID != testID {
+		return nil, fmt.Errorf("attempt %d for test %d %w", attemptID, testID, ErrNotFound)
+	}
+
+	answers, err := s.Repo.GetSavedAnswers(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.shownQuestionOrder(ctx, attempt, nil)
+	if err != nil {
+		return nil, err
 	}
-	question := questions[0]
+	sortByQuestionOrder(answers, order, func(a models.SavedAnswer) int { return a.QuestionID })
+
+	return &models.AttemptProgress{Attempt: attempt, Answers: answers}, nil
+}
+
+// GetAttemptReview возвращает разбор завершенной попытки: ответы с баллами,
+// комментариями проверяющего и разбором ошибок. Студент видит свою попытку
+// после открытия результатов, преподаватель курса — любую попытку.
+func (s *TestService) GetAttemptReview(ctx context.Context, userID, attemptID int) (*models.AttemptReview, error) {
+	attempt, err := s.Repo.GetAttemptByID(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	test, err := s.Repo.GetTestByID(ctx, strconv.Itoa(attempt.TestID))
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.UserID == userID {
+		if attempt.Status == "in_progress" {
+			return nil, fmt.Errorf("%w: attempt %d is not finished", ErrConflict, attempt.ID)
+		}
+		if !test.ResultsReleased {
+			return nil, fmt.Errorf("%w: results of test %d are not released", ErrForbidden, test.ID)
+		}
+	} else if err := s.checkCourseTeacher(ctx, userID, test.CourseID); err != nil {
+		return nil, err
+	}
+
+	answers, err := s.Repo.GetAttemptAnswers(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.shownQuestionOrder(ctx, attempt, test)
+	if err != nil {
+		return nil, err
+	}
+	sortByQuestionOrder(answers, order, func(a models.UserAnswer) int { return a.QuestionID })
+
+	return &models.AttemptReview{Attempt: attempt, Answers: answers}, nil
+}
+
+// evaluateAnswer возвращает начисленные баллы и признак того, что ответ
+// нужно проверить вручную. Попытка нужна сгенерированным упражнениям:
+// ключ ответа вычисляется по ее варианту.
+func (s *TestService) evaluateAnswer(ctx context.Context, attempt *models.TestAttempt, question *models.Question, answerData json.RawMessage) (int, bool, error) {
+	questionID := question.ID
 
 	// Получаем правильные ответы (если нужно)
 	var options []models.AnswerOption
+	var err error
 	if question.QuestionType == "single_choice" || question.QuestionType == "multiple_choice" {
-		options, err = s.Repo.GetQuestionOptions(ctx, questionID)
+		options, err = s.Repo.GetQuestionAnswerKey(ctx, questionID)
 		if err != nil {
-			return 0, err
+			return 0, false, err
 		}
 	}
 
@@ -110,28 +433,28 @@ func (s *TestService) evaluateAnswer(ctx context.Context, questionID int, answer
 	switch question.QuestionType {
 	case "single_choice":
 		if len(options) == 0 {
-			return 0, nil // Если нет вариантов
+			return 0, false, nil // Если нет вариантов
 		}
 		var answer struct {
 			SelectedOptionID int `json:"selected_option_id"`
 		}
 		if err := json.Unmarshal(answerData, &answer); err != nil {
-			return 0, err
+			return 0, false, err
 		}
 
 		for _, opt := range options {
 			if opt.ID == answer.SelectedOptionID && opt.IsCorrect {
-				return question.Points, nil
+				return question.Points, false, nil
 			}
 		}
-		return 0, nil
+		return 0, false, nil
 
 	case "multiple_choice":
 		var answer struct {
 			SelectedOptionIDs []int `json:"selected_option_ids"`
 		}
 		if err := json.Unmarshal(answerData, &answer); err != nil {
-			return 0, err
+			return 0, false, err
 		}
 
 		correctOptions := 0
@@ -153,78 +476,161 @@ func (s *TestService) evaluateAnswer(ctx context.Context, questionID int, answer
 
 		// Начисляем пропорционально количеству правильных ответов
 		if correctOptions > 0 {
-			return (question.Points * userCorrect) / correctOptions, nil
+			return (question.Points * userCorrect) / correctOptions, false, nil
 		}
-		return 0, nil
+		return 0, false, nil
 
 	case "text_answer":
-		// TODO: сложная проверка
+		rules, err := s.Repo.GetTextAnswerRules(ctx, questionID)
+		if err != nil {
+			return 0, false, err
+		}
+		var answer models.TextAnswer
+		if err := json.Unmarshal(answerData, &answer); err != nil {
+			return 0, false, err
+		}
 
-		return 0, nil
+		// Ответ, не подошедший ни под одно правило, отправляется на ручную проверку
+		if gradeTextAnswer(rules, answer.AnswerText) {
+			return question.Points, false, nil
+		}
+		return 0, true, nil
 
 	case "matching":
-		// TODO: проверка
-		return 0, nil
+		pairs, err := s.Repo.GetMatchingPairs(ctx, questionID)
+		if err != nil {
+			return 0, false, err
+		}
+		var answer models.MatchingAnswer
+		if err := json.Unmarshal(answerData, &answer); err != nil {
+			return 0, false, err
+		}
+		return gradeMatching(question, pairs, answer), false, nil
+
+	case "relation":
+		spec, err := s.Repo.GetRelationSpec(ctx, questionID)
+		if err != nil {
+			return 0, false, err
+		}
+		var answer models.RelationAnswer
+		if err := json.Unmarshal(answerData, &answer); err != nil {
+			return 0, false, err
+		}
+		points, err := gradeRelation(question, spec, answer, attempt)
+		return points, false, err
 
 	default:
-		return 0, errors.New("unknown question type")
+		return 0, false, errors.New("unknown question type")
 	}
 }
 
-func (s *TestService) FinishAttempt(ctx context.Context, userID, attemptID int) (*models.TestAttempt, error) {
+// FinishAttempt завершает попытку пользователя по тесту. Если attemptID равен 0,
+// завершается текущая незавершенная попытка пользователя по testID.
+func (s *TestService) FinishAttempt(ctx context.Context, userID, testID, attemptID int) (*models.TestAttempt, error) {
+	if attemptID == 0 {
+		active, err := s.Repo.GetActiveAttempt(ctx, userID, testID)
+		if err != nil {
+			return nil, err
+		}
+		attemptID = active.ID
+	}
+
 	// Проверяем, принадлежит ли attempt пользователю
+	attempt, err := s.ownedActiveAttempt(ctx, userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if testID != 0 && attempt.TestID != testID {
+		return nil, fmt.Errorf("attempt %d for test %d %w", attemptID, testID, ErrNotFound)
+	}
 
 	// Считаем общий балл за попытку
-	totalScore, err := s.calculateAttemptScore(ctx, attemptID)
+	totalScore, maxScore, err := s.calculateAttemptScore(ctx, attemptID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Если есть ответы на ручную проверку, попытка ждет преподавателя
+	pending, err := s.Repo.CountPendingAnswers(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	status := "completed"
+	if pending > 0 {
+		status = "pending_review"
+	}
+
 	// Обновляем попытку
-	if err := s.Repo.CompleteAttempt(ctx, attemptID, totalScore); err != nil {
+	updated, err := s.Repo.CompleteAttempt(ctx, attemptID, totalScore, maxScore, scorePercentage(totalScore, maxScore), status)
+	if err != nil {
 		return nil, err
 	}
+	if !updated {
+		return nil, fmt.Errorf("%w: attempt %d is already finished", ErrConflict, attemptID)
+	}
 
 	// Возвращаем обновленную попытку
-	// TODO: добавить метод для получения попытки
-	return &models.TestAttempt{
-		ID:     attemptID,
-		UserID: userID,
-		Score:  &totalScore,
-		Status: "completed",
-	}, nil
+	return s.Repo.GetAttemptByID(ctx, attemptID)
+}
+
+// ownedActiveAttempt возвращает попытку, если она принадлежит пользователю и еще не завершена
+func (s *TestService) ownedActiveAttempt(ctx context.Context, userID, attemptID int) (*models.TestAttempt, error) {
+	attempt, err := s.Repo.GetAttemptByID(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.UserID != userID {
+		return nil, fmt.Errorf("%w: attempt %d belongs to another user", ErrForbidden, attemptID)
+	}
+	if attempt.Status != "in_progress" {
+		return nil, fmt.Errorf("%w: attempt %d is %s", ErrConflict, attemptID, attempt.Status)
+	}
+
+	return attempt, nil
+}
+
+// calculateAttemptScore возвращает набранный балл и максимально возможный балл за попытку
+func (s *TestService) calculateAttemptScore(ctx context.Context, attemptID int) (int, int, error) {
+	attempt, err := s.Repo.GetAttemptByID(ctx, attemptID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	score, err := s.Repo.SumAttemptPoints(ctx, attemptID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum attempt points: %w", err)
+	}
+
+	// Максимальный балл считается по вопросам, выпавшим именно этой попытке
+	questions, err := s.attemptQuestions(ctx, attempt)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get max score: %w", err)
+	}
+	maxScore := 0
+	for _, q := range questions {
+		maxScore += q.Points
+	}
+
+	return score, maxScore, nil
 }
 
-func (s *TestService) calculateAttemptScore(ctx context.Context, attemptID int) (int, error) {
-	// TODO: запрашивать все ответы и суммировать баллы
-	// Здесь упрощенный вариант
-	return 0, nil
+// scorePercentage возвращает процент выполнения, округленный до сотых
+func scorePercentage(score, maxScore int) float64 {
+	if maxScore <= 0 {
+		return 0
+	}
+	return math.Round(float64(score)*10000/float64(maxScore)) / 100
 }
 
-func (s *TestService) CreateTest(ctx context.Context, req *models.CreateTestRequest) (*models.Test, error) {
+func (s *TestService) CreateTest(ctx context.Context, teacherID int, req *models.CreateTestRequest) (*models.Test, error) {
 	// Валидация (используйте github.com/go-playground/validator)
 	// if err := validator.New().Struct(req); err != nil {
 	//     return nil, err
 	// }
 
-	// Проверка вопросов и ответов
-	for _, q := range req.Questions {
-		if (q.QuestionType == "single_choice" || q.QuestionType == "multiple_choice") && len(q.Options) == 0 {
-			return nil, fmt.Errorf("question type %s requires options", q.QuestionType)
-		}
-
-		// Для single_choice проверяем, что есть ровно один правильный ответ
-		if q.QuestionType == "single_choice" {
-			correctCount := 0
-			for _, opt := range q.Options {
-				if opt.IsCorrect {
-					correctCount++
-				}
-			}
-			if correctCount != 1 {
-				return nil, errors.New("single_choice questions must have exactly one correct option")
-			}
-		}
+	if err := s.validateTestRequest(ctx, teacherID, req); err != nil {
+		return nil, err
 	}
 
 	// Создаем тест
@@ -234,6 +640,11 @@ func (s *TestService) CreateTest(ctx context.Context, req *models.CreateTestRequ
 		EndDate:  req.EndDate,
 		Duration: req.Duration,
 		Attempts: req.Attempts,
+
+		ShuffleQuestions: req.ShuffleQuestions,
+		ShuffleOptions:   req.ShuffleOptions,
+		Version:          1,
+		ExternalID:       req.ExternalID,
 	}
 
 	// Начинаем транзакцию
@@ -250,39 +661,339 @@ func (s *TestService) CreateTest(ctx context.Context, req *models.CreateTestRequ
 		return nil, fmt.Errorf("failed to create test: %w", err)
 	}
 
+	// Срок из пакета отсчитывается от даты создания, которую назначает БД
+	if req.EndDateOffset != nil {
+		test.EndDate = test.UploadDate.Add(time.Duration(*req.EndDateOffset) * time.Second)
+		if err := txRepo.UpdateTest(ctx, test); err != nil {
+			return nil, fmt.Errorf("failed to set test end date: %w", err)
+		}
+	}
+
+	if err := createTestContent(ctx, txRepo, test.ID, req); err != nil {
+		return nil, err
+	}
+
+	// Фиксируем транзакцию
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return test, nil
+}
+
+// UpdateTest заменяет настройки и вопросы теста. Если по тесту уже есть попытки,
+// создается новая версия: прежние вопросы архивируются и остаются у начатых
+// попыток, а новые попытки получают новые вопросы.
+func (s *TestService) UpdateTest(ctx context.Context, teacherID int, req *models.UpdateTestRequest) (*models.Test, error) {
+	test, err := s.Repo.GetTestByID(ctx, strconv.Itoa(req.TestID))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCourseTeacher(ctx, teacherID, test.CourseID); err != nil {
+		return nil, err
+	}
+
+	// Тест не переносится между курсами
+	req.CourseID = test.CourseID
+	if err := s.validateTestRequest(ctx, teacherID, &req.CreateTestRequest); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.Repo.CountTestAttempts(ctx, test.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	test.Title = req.Title
+	test.EndDate = req.EndDate
+	test.Duration = req.Duration
+	test.Attempts = req.Attempts
+	test.ShuffleQuestions = req.ShuffleQuestions
+	test.ShuffleOptions = req.ShuffleOptions
+	if attempts > 0 {
+		test.Version++
+	}
+
+	tx, err := s.Repo.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txRepo := repository.NewTxRepository(tx)
+
+	if err := txRepo.UpdateTest(ctx, test); err != nil {
+		return nil, fmt.Errorf("failed to update test: %w", err)
+	}
+
+	if attempts > 0 {
+		err = txRepo.ArchiveTestContent(ctx, test.ID)
+	} else {
+		err = txRepo.DeleteTestContent(ctx, test.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace test content: %w", err)
+	}
+
+	if err := createTestContent(ctx, txRepo, test.ID, &req.CreateTestRequest); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return test, nil
+}
+
+// DeleteTest удаляет тест. Тест с попытками только скрывается, чтобы не потерять оценки.
+func (s *TestService) DeleteTest(ctx context.Context, teacherID, testID int) error {
+	test, err := s.Repo.GetTestByID(ctx, strconv.Itoa(testID))
+	if err != nil {
+		return err
+	}
+	if err := s.checkCourseTeacher(ctx, teacherID, test.CourseID); err != nil {
+		return err
+	}
+
+	attempts, err := s.Repo.CountTestAttempts(ctx, testID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.Repo.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	txRepo := repository.NewTxRepository(tx)
+
+	if attempts > 0 {
+		err = txRepo.SoftDeleteTest(ctx, testID)
+	} else {
+		err = txRepo.DeleteTests(ctx, []int{testID})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete test: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// validateTestRequest проверяет разделы и вопросы теста до сохранения;
+// вопросы банка должны быть доступны преподавателю teacherID
+func (s *TestService) validateTestRequest(ctx context.Context, teacherID int, req *models.CreateTestRequest) error {
+	if err := validateSections(req); err != nil {
+		return err
+	}
+
+	// Проверка вопросов и ответов
+	for i := range req.Questions {
+		// Вопросы банка уже проверены при сохранении в банк
+		if req.Questions[i].BankQuestionID > 0 {
+			if err := s.checkBankQuestion(ctx, teacherID, req.Questions[i].BankQuestionID, req.CourseID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		normalizeQuestion(&req.Questions[i])
+		if err := validateQuestion(req.Questions[i]); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+	}
+
+	return nil
+}
+
+// testContentWriter — часть репозитория транзакции, которая сохраняет разделы и вопросы теста
+type testContentWriter interface {
+	questionContentWriter
+	CreateSection(context.Context, *models.TestSection) error
+	CreateQuestion(context.Context, *models.Question) error
+	AddTestBankQuestion(ctx context.Context, testID, questionID, position int, sectionID *int) error
+}
+
+// createTestContent сохраняет разделы и вопросы теста из запроса
+func createTestContent(ctx context.Context, txRepo testContentWriter, testID int, req *models.CreateTestRequest) error {
+	// Сохраняем разделы; вопросы ссылаются на них по номеру
+	sectionIDs := make([]int, len(req.Sections))
+	for i, secReq := range req.Sections {
+		section := &models.TestSection{
+			TestID:    testID,
+			Title:     secReq.Title,
+			DrawCount: secReq.DrawCount,
+			Position:  secReq.Position,
+		}
+		if err := txRepo.CreateSection(ctx, section); err != nil {
+			return fmt.Errorf("failed to create section: %w", err)
+		}
+		sectionIDs[i] = section.ID
+	}
+
 	// Сохраняем вопросы и варианты ответов
 	for _, qReq := range req.Questions {
+		var sectionID *int
+		if qReq.Section > 0 {
+			sectionID = &sectionIDs[qReq.Section-1]
+		}
+
+		// Вопрос банка не копируется: тест хранит только ссылку
+		if qReq.BankQuestionID > 0 {
+			if err := txRepo.AddTestBankQuestion(ctx, testID, qReq.BankQuestionID, qReq.Position, sectionID); err != nil {
+				return fmt.Errorf("failed to add bank question: %w", err)
+			}
+			continue
+		}
+
 		question := &models.Question{
-			TestID:       test.ID,
+			TestID:       testID,
 			QuestionText: qReq.QuestionText,
 			QuestionType: qReq.QuestionType,
 			Points:       qReq.Points,
 			Position:     qReq.Position,
+			ScoringMode:  qReq.ScoringMode,
+			SectionID:    sectionID,
+		}
+		if question.ScoringMode == "" {
+			question.ScoringMode = scoringAllOrNothing
 		}
 
 		if err := txRepo.CreateQuestion(ctx, question); err != nil {
-			return nil, fmt.Errorf("failed to create question: %w", err)
+			return fmt.Errorf("failed to create question: %w", err)
 		}
 
-		// Сохраняем варианты ответов (если есть)
-		for _, optReq := range qReq.Options {
-			option := &models.AnswerOption{
-				QuestionID: question.ID,
-				OptionText: optReq.OptionText,
-				IsCorrect:  optReq.IsCorrect,
-				Position:   optReq.Position,
-			}
+		if err := createQuestionContent(ctx, txRepo, question.ID, qReq); err != nil {
+			return err
+		}
+	}
 
-			if err := txRepo.CreateAnswerOption(ctx, option); err != nil {
-				return nil, fmt.Errorf("failed to create answer option: %w", err)
+	return nil
+}
+
+// normalizeQuestion приводит тип вопроса к тому, что хранится в БД
+func normalizeQuestion(q *models.CreateQuestionRequest) {
+	// Редактор тестов отправляет открытые вопросы с типом "text"
+	if q.QuestionType == "text" {
+		q.QuestionType = "text_answer"
+	}
+	if q.Relation != nil {
+		normalizeRelationSpec(q.Relation)
+	}
+}
+
+// validateQuestion проверяет вопрос с вариантами ответов до сохранения
+func validateQuestion(q models.CreateQuestionRequest) error {
+	switch q.QuestionType {
+	case "single_choice", "multiple_choice", "text_answer", "matching", "relation":
+	default:
+		return fmt.Errorf("unknown question type %s", q.QuestionType)
+	}
+
+	if (q.QuestionType == "single_choice" || q.QuestionType == "multiple_choice") && len(q.Options) == 0 {
+		return fmt.Errorf("question type %s requires options", q.QuestionType)
+	}
+
+	for _, rule := range q.AnswerRules {
+		if err := validateTextAnswerRule(rule); err != nil {
+			return err
+		}
+	}
+
+	if q.QuestionType == "matching" && len(q.Pairs) == 0 {
+		return errors.New("matching questions require pairs")
+	}
+
+	if q.QuestionType == "relation" {
+		if err := validateRelationSpec(q.Relation); err != nil {
+			return err
+		}
+	}
+
+	if q.ScoringMode != "" && q.ScoringMode != scoringAllOrNothing && q.ScoringMode != scoringPartial {
+		return fmt.Errorf("unknown scoring mode %s", q.ScoringMode)
+	}
+
+	// Для single_choice проверяем, что есть ровно один правильный ответ
+	if q.QuestionType == "single_choice" {
+		correctCount := 0
+		for _, opt := range q.Options {
+			if opt.IsCorrect {
+				correctCount++
 			}
 		}
+		if correctCount != 1 {
+			return errors.New("single_choice questions must have exactly one correct option")
+		}
 	}
 
-	// Фиксируем транзакцию
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	return nil
+}
+
+// questionContentWriter — часть репозитория транзакции, которая сохраняет
+// содержимое вопроса (тип репозитория транзакции не экспортируется)
+type questionContentWriter interface {
+	CreateTextAnswerRule(context.Context, *models.TextAnswerRule) error
+	CreateMatchingPair(context.Context, *models.MatchingPair) error
+	CreateAnswerOption(context.Context, *models.AnswerOption) error
+	CreateRelationSpec(context.Context, int, *models.RelationSpec) error
+}
+
+// createQuestionContent сохраняет правила, пары, варианты ответа и задание
+// вопроса об отношении для нового вопроса
+func createQuestionContent(ctx context.Context, txRepo questionContentWriter, questionID int, qReq models.CreateQuestionRequest) error {
+	// Сохраняем правила проверки текстового ответа (если есть)
+	for _, ruleReq := range qReq.AnswerRules {
+		rule := &models.TextAnswerRule{
+			QuestionID: questionID,
+			RuleType:   ruleReq.RuleType,
+			Pattern:    ruleReq.Pattern,
+			Tolerance:  ruleReq.Tolerance,
+			Position:   ruleReq.Position,
+		}
+
+		if err := txRepo.CreateTextAnswerRule(ctx, rule); err != nil {
+			return fmt.Errorf("failed to create answer rule: %w", err)
+		}
 	}
 
-	return test, nil
+	// Сохраняем пары сопоставления (если есть)
+	rightKeys := newRightKeys(len(qReq.Pairs))
+	for i, pairReq := range qReq.Pairs {
+		pair := &models.MatchingPair{
+			QuestionID: questionID,
+			LeftText:   pairReq.LeftText,
+			RightText:  pairReq.RightText,
+			RightKey:   rightKeys[i],
+			Position:   pairReq.Position,
+		}
+
+		if err := txRepo.CreateMatchingPair(ctx, pair); err != nil {
+			return fmt.Errorf("failed to create matching pair: %w", err)
+		}
+	}
+
+	// Сохраняем варианты ответов (если есть)
+	for _, optReq := range qReq.Options {
+		option := &models.AnswerOption{
+			QuestionID: questionID,
+			OptionText: optReq.OptionText,
+			IsCorrect:  optReq.IsCorrect,
+			Position:   optReq.Position,
+		}
+
+		if err := txRepo.CreateAnswerOption(ctx, option); err != nil {
+			return fmt.Errorf("failed to create answer option: %w", err)
+		}
+	}
+
+	// Сохраняем задание вопроса об отношении (если есть)
+	if qReq.QuestionType == "relation" && qReq.Relation != nil {
+		if err := txRepo.CreateRelationSpec(ctx, questionID, qReq.Relation); err != nil {
+			return fmt.Errorf("failed to create relation spec: %w", err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"api/internal/models"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Типы правил проверки текстового ответа
+const (
+	ruleExact      = "exact"      // точное совпадение без учета пробелов по краям
+	ruleNormalized = "normalized" // без учета регистра, лишних пробелов и ё/е
+	ruleRegex      = "regex"      // регулярное выражение на весь ответ
+	ruleNumeric    = "numeric"    // число с допустимой погрешностью
+	rulePairSet    = "pair_set"   // множество пар вида {(1,2),(2,3)} без учета порядка
+)
+
+// validateTextAnswerRule проверяет правило при создании вопроса,
+// чтобы ошибка в шаблоне не всплыла только во время прохождения теста
+func validateTextAnswerRule(rule models.CreateTextAnswerRuleRequest) error {
+	switch rule.RuleType {
+	case ruleExact, ruleNormalized:
+		return nil
+	case ruleRegex:
+		if _, err := compileAnswerRegex(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", rule.Pattern, err)
+		}
+		return nil
+	case ruleNumeric:
+		if _, err := parseAnswerNumber(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid number %q", rule.Pattern)
+		}
+		if rule.Tolerance < 0 {
+			return fmt.Errorf("tolerance must not be negative")
+		}
+		return nil
+	case rulePairSet:
+		if _, err := parsePairSet(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pair set %q: %w", rule.Pattern, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown rule type %s", rule.RuleType)
+	}
+}
+
+// gradeTextAnswer возвращает true, если ответ подходит хотя бы под одно правило
+func gradeTextAnswer(rules []models.TextAnswerRule, answer string) bool {
+	for _, rule := range rules {
+		if matchTextAnswerRule(rule, answer) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTextAnswerRule(rule models.TextAnswerRule, answer string) bool {
+	switch rule.RuleType {
+	case ruleExact:
+		return strings.TrimSpace(answer) == strings.TrimSpace(rule.Pattern)
+	case ruleNormalized:
+		return normalizeAnswer(answer) == normalizeAnswer(rule.Pattern)
+	case ruleRegex:
+		re, err := compileAnswerRegex(rule.Pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(strings.TrimSpace(answer))
+	case ruleNumeric:
+		expected, err := parseAnswerNumber(rule.Pattern)
+		if err != nil {
+			return false
+		}
+		got, err := parseAnswerNumber(answer)
+		if err != nil {
+			return false
+		}
+		// Небольшой запас на погрешность представления float
+		return math.Abs(got-expected) <= rule.Tolerance+1e-9
+	case rulePairSet:
+		expected, err := parsePairSet(rule.Pattern)
+		if err != nil {
+			return false
+		}
+		got, err := parsePairSet(answer)
+		if err != nil {
+			return false
+		}
+		return equalPairSets(expected, got)
+	default:
+		return false
+	}
+}
+
+// normalizeAnswer приводит ответ к нижнему регистру, схлопывает пробелы и заменяет ё на е
+func normalizeAnswer(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "ё", "е")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// compileAnswerRegex компилирует шаблон так, чтобы он совпадал со всем ответом
+func compileAnswerRegex(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+// parseAnswerNumber разбирает число, допуская запятую в качестве десятичного разделителя
+func parseAnswerNumber(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	return strconv.ParseFloat(s, 64)
+}
+
+type answerPair struct {
+	a, b string
+}
+
+// parsePairSet разбирает запись вида {(1,2),(2,3)}. Пустое множество — {} или ∅.
+func parsePairSet(s string) (map[answerPair]bool, error) {
+	s = strings.TrimSpace(s)
+	set := make(map[answerPair]bool)
+	if s == "∅" {
+		return set, nil
+	}
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("set must be enclosed in braces")
+	}
+	body := strings.TrimSpace(s[1 : len(s)-1])
+
+	for body != "" {
+		if body[0] != '(' {
+			return nil, fmt.Errorf("expected '(' near %q", body)
+		}
+		end := strings.IndexByte(body, ')')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed pair near %q", body)
+		}
+		parts := strings.Split(body[1:end], ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("pair must have two elements: %q", body[:end+1])
+		}
+		a, b := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if a == "" || b == "" {
+			return nil, fmt.Errorf("empty element in pair %q", body[:end+1])
+		}
+		set[answerPair{a, b}] = true
+
+		body = strings.TrimSpace(body[end+1:])
+		if body == "" {
+			break
+		}
+		if body[0] != ',' {
+			return nil, fmt.Errorf("expected ',' between pairs near %q", body)
+		}
+		body = strings.TrimSpace(body[1:])
+		if body == "" {
+			return nil, fmt.Errorf("trailing comma")
+		}
+	}
+
+	return set, nil
+}
+
+func equalPairSets(a, b map[answerPair]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for p := range a {
+		if !b[p] {
+			return false
+		}
+	}
+	return true
+}
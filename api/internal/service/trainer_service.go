@@ -0,0 +1,132 @@
+package service
+
+import (
+	"api/internal/models"
+	"api/internal/relation"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Перерыв между проверками, после которого сеанс в тренажере считается оконченным
+const trainerIdleGap = 10 * time.Minute
+
+const maxTrainerRelationName = 255
+
+// SaveTrainerRelation сохраняет отношение тренажера под именем;
+// отношение с тем же именем перезаписывается
+func (s *TestService) SaveTrainerRelation(ctx context.Context, userID int, req *models.TrainerRelationRequest) (*models.TrainerRelation, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxTrainerRelationName {
+		return nil, fmt.Errorf("%w: name must have from 1 to %d characters", ErrInvalidInput, maxTrainerRelationName)
+	}
+
+	set, err := relation.NewSet(req.Elements...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if set.Len() == 0 || set.Len() > maxRelationElements {
+		return nil, fmt.Errorf("%w: relation must have from 1 to %d elements", ErrInvalidInput, maxRelationElements)
+	}
+	r, err := answerRelation(set, models.RelationAnswer{Pairs: req.Pairs, Matrix: req.Matrix})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	rel := &models.TrainerRelation{Name: name, Elements: set.Elements(), Pairs: []models.RelationPair{}}
+	for _, p := range r.Pairs() {
+		rel.Pairs = append(rel.Pairs, models.RelationPair{From: p.From, To: p.To})
+	}
+	if err := s.Repo.SaveTrainerRelation(ctx, userID, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+func (s *TestService) GetTrainerRelations(ctx context.Context, userID int) ([]models.TrainerRelation, error) {
+	return s.Repo.GetTrainerRelations(ctx, userID)
+}
+
+func (s *TestService) GetTrainerRelation(ctx context.Context, userID, relationID int) (*models.TrainerRelation, error) {
+	return s.Repo.GetTrainerRelation(ctx, userID, relationID)
+}
+
+func (s *TestService) DeleteTrainerRelation(ctx context.Context, userID, relationID int) error {
+	return s.Repo.DeleteTrainerRelation(ctx, userID, relationID)
+}
+
+// CheckTrainerRelation объясняет свойства отношения, как ExplainRelation,
+// и записывает проверку в журнал тренажера. Ошибка записи не мешает
+// студенту получить результат проверки.
+func (s *TestService) CheckTrainerRelation(ctx context.Context, userID int, req *models.TrainerCheckRequest) (*models.ExplainRelationResponse, error) {
+	response, err := s.ExplainRelation(&req.ExplainRelationRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &models.TrainerCheck{UserID: userID, RelationKey: relationKey(response.Relation)}
+	if req.Condition != "" {
+		check.Condition = response.Explanations[0].Condition
+		check.Holds = &response.Explanations[0].Holds
+	}
+	if err := s.Repo.LogTrainerCheck(ctx, check); err != nil {
+		log.Println("Ошибка записи проверки тренажера(файл trainer_service метод CheckTrainerRelation) " + err.Error())
+	}
+	return response, nil
+}
+
+// GetTrainerActivity возвращает практику группы в тренажере рядом с
+// результатами тестов. Преподаватель видит только группы своих курсов.
+func (s *TestService) GetTrainerActivity(ctx context.Context, teacherID int, req *models.TrainerActivityRequest) (*models.TrainerActivity, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, fmt.Errorf("%w: period start must be before its end", ErrInvalidInput)
+	}
+
+	groups, err := s.Repo.GetTeacherGroups(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	activity := &models.TrainerActivity{Groups: groups, GroupID: req.GroupID, Students: []models.StudentPractice{}}
+	if activity.GroupID == 0 {
+		if len(groups) == 0 {
+			return activity, nil
+		}
+		activity.GroupID = groups[0].Id
+	}
+	if !slices.ContainsFunc(groups, func(g models.Group) bool { return g.Id == activity.GroupID }) {
+		return nil, fmt.Errorf("%w: group %d does not study this teacher's courses", ErrForbidden, activity.GroupID)
+	}
+
+	activity.Students, err = s.Repo.GetGroupPractice(ctx, teacherID, activity.GroupID, req.From, req.To, trainerIdleGap)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// relationKey — ключ канонического вида отношения: одинаковые отношения,
+// построенные в тренажере разными путями, считаются одним исследованным
+func relationKey(r *relation.Relation) string {
+	var b strings.Builder
+	for _, e := range r.Set().Elements() {
+		b.WriteString(e)
+		b.WriteByte(0)
+	}
+	for _, row := range r.Matrix() {
+		for _, v := range row {
+			if v {
+				b.WriteByte('1')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"api/internal/models"
+	"api/internal/relation"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Уровни сложности заданий тренажера
+const (
+	trainerLevelBasics   = 1 // одно-два свойства
+	trainerLevelCombined = 2 // сочетания свойств
+	trainerLevelClasses  = 3 // виды отношений
+	trainerLevelSubtle   = 4 // сочетания, которые кажутся невозможными
+	trainerLevelCount    = 4
+)
+
+// Условия заданий сверх свойств и видов отношения — в поле condition нарушений
+const (
+	trainerConditionClasses = "classes"
+	trainerConditionLattice = "lattice"
+	trainerConditionPairs   = "pairs"
+)
+
+// trainerTasks — задания тренажера в порядке прохождения. Выполнимость каждого
+// задания проверена перебором: на множествах до четырех элементов это быстро.
+var trainerTasks = []models.TrainerTask{
+	{
+		ID: "reflexive-not-symmetric", Level: trainerLevelBasics, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} рефлексивное, но не симметричное отношение",
+		Required:  []string{"reflexive"},
+		Forbidden: []string{"symmetric"},
+	},
+	{
+		ID: "symmetric-not-reflexive", Level: trainerLevelBasics, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} симметричное отношение, которое не рефлексивно и не иррефлексивно",
+		Required:  []string{"symmetric"},
+		Forbidden: []string{"reflexive", "irreflexive"},
+	},
+	{
+		ID: "antisymmetric-not-transitive", Level: trainerLevelBasics, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} антисимметричное, но не транзитивное отношение",
+		Required:  []string{"antisymmetric"},
+		Forbidden: []string{"transitive"},
+	},
+	{
+		ID: "reflexive-symmetric-not-transitive", Level: trainerLevelCombined, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} рефлексивное и симметричное, но не транзитивное отношение",
+		Required:  []string{"reflexive", "symmetric"},
+		Forbidden: []string{"transitive"},
+	},
+	{
+		ID: "reflexive-transitive-not-symmetric", Level: trainerLevelCombined, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} рефлексивное и транзитивное, но не симметричное отношение",
+		Required:  []string{"reflexive", "transitive"},
+		Forbidden: []string{"symmetric"},
+	},
+	{
+		ID: "irreflexive-symmetric-not-transitive", Level: trainerLevelCombined, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} иррефлексивное и симметричное, но не транзитивное отношение",
+		Required:  []string{"irreflexive", "symmetric"},
+		Forbidden: []string{"transitive"},
+	},
+	{
+		ID: "neither-symmetric-nor-antisymmetric", Level: trainerLevelCombined, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} транзитивное отношение, которое не симметрично и не антисимметрично",
+		Required:  []string{"transitive"},
+		Forbidden: []string{"symmetric", "antisymmetric"},
+	},
+	{
+		ID: "equivalence-two-classes", Level: trainerLevelClasses, Elements: []string{"1", "2", "3", "4"},
+		Prompt:   "Постройте на множестве {1, 2, 3, 4} отношение эквивалентности ровно с двумя классами",
+		Required: []string{"equivalence"},
+		Classes:  2,
+	},
+	{
+		ID: "partial-order-not-linear", Level: trainerLevelClasses, Elements: []string{"1", "2", "3", "4"},
+		Prompt:    "Постройте на множестве {1, 2, 3, 4} частичный порядок, который не является линейным",
+		Required:  []string{"partial_order"},
+		Forbidden: []string{"linear_order"},
+	},
+	{
+		ID: "strict-order", Level: trainerLevelClasses, Elements: []string{"1", "2", "3", "4"},
+		Prompt:   "Постройте на множестве {1, 2, 3, 4} строгий порядок, в котором не меньше четырех пар",
+		Required: []string{"strict_order"},
+		MinPairs: 4,
+	},
+	{
+		ID: "function-neither-reflexive-nor-symmetric", Level: trainerLevelClasses, Elements: []string{"1", "2", "3", "4"},
+		Prompt:    "Постройте на множестве {1, 2, 3, 4} функцию, которая не рефлексивна и не симметрична",
+		Required:  []string{"function"},
+		Forbidden: []string{"reflexive", "symmetric"},
+	},
+	{
+		ID: "symmetric-transitive-not-reflexive", Level: trainerLevelSubtle, Elements: []string{"1", "2", "3", "4"},
+		Prompt:    "Постройте на множестве {1, 2, 3, 4} симметричное и транзитивное, но не рефлексивное отношение, в котором не меньше трех пар",
+		Required:  []string{"symmetric", "transitive"},
+		Forbidden: []string{"reflexive"},
+		MinPairs:  3,
+	},
+	{
+		ID: "symmetric-and-antisymmetric", Level: trainerLevelSubtle, Elements: []string{"1", "2", "3", "4"},
+		Prompt:    "Постройте на множестве {1, 2, 3, 4} отношение, которое одновременно симметрично и антисимметрично, но не рефлексивно и не иррефлексивно",
+		Required:  []string{"symmetric", "antisymmetric"},
+		Forbidden: []string{"reflexive", "irreflexive"},
+	},
+	{
+		ID: "preorder", Level: trainerLevelSubtle, Elements: []string{"1", "2", "3"},
+		Prompt:    "Постройте на множестве {1, 2, 3} рефлексивное и транзитивное отношение, которое не симметрично и не антисимметрично",
+		Required:  []string{"reflexive", "transitive"},
+		Forbidden: []string{"symmetric", "antisymmetric"},
+	},
+	{
+		ID: "partial-order-not-lattice", Level: trainerLevelSubtle, Elements: []string{"1", "2", "3", "4"},
+		Prompt:     "Постройте на множестве {1, 2, 3, 4} частичный порядок, который не является решеткой",
+		Required:   []string{"partial_order"},
+		NotLattice: true,
+	},
+}
+
+func findTrainerTask(id string) (*models.TrainerTask, error) {
+	for i := range trainerTasks {
+		if trainerTasks[i].ID == id {
+			task := trainerTasks[i]
+			return &task, nil
+		}
+	}
+	return nil, fmt.Errorf("trainer task %q %w", id, ErrNotFound)
+}
+
+// GetTrainerTasks возвращает задания уровня (или всех уровней) с прогрессом студента
+func (s *TestService) GetTrainerTasks(ctx context.Context, userID, level int) ([]models.TrainerTask, error) {
+	if level < 0 || level > trainerLevelCount {
+		return nil, fmt.Errorf("%w: level must be from 1 to %d", ErrInvalidInput, trainerLevelCount)
+	}
+	progress, err := s.Repo.GetTrainerTaskProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []models.TrainerTask{}
+	for _, task := range trainerTasks {
+		if level != 0 && task.Level != level {
+			continue
+		}
+		p := progress[task.ID]
+		task.Attempts, task.SolvedAt = p.Attempts, p.SolvedAt
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// VerifyTrainerTask проверяет решение задания и засчитывает попытку.
+// Если решение неверно, возвращаются все нарушенные условия с контрпримерами.
+func (s *TestService) VerifyTrainerTask(ctx context.Context, userID int, req *models.TrainerTaskRequest) (*models.TrainerTaskVerdict, error) {
+	task, err := findTrainerTask(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	set, err := relation.NewSet(task.Elements...)
+	if err != nil {
+		return nil, err
+	}
+	r, err := answerRelation(set, models.RelationAnswer{Pairs: req.Pairs, Matrix: req.Matrix})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	violations, err := trainerTaskViolations(task, r)
+	if err != nil {
+		return nil, err
+	}
+	verdict := &models.TrainerTaskVerdict{TaskID: task.ID, Solved: len(violations) == 0, Relation: r, Violations: violations}
+
+	progress, err := s.Repo.RecordTrainerTaskAttempt(ctx, userID, task.ID, verdict.Solved)
+	if err != nil {
+		return nil, err
+	}
+	verdict.Attempts, verdict.SolvedAt = progress.Attempts, progress.SolvedAt
+	return verdict, nil
+}
+
+// trainerTaskViolations перечисляет условия задания, которые отношение нарушает
+func trainerTaskViolations(task *models.TrainerTask, r *relation.Relation) ([]relation.Explanation, error) {
+	violations := []relation.Explanation{}
+	for _, name := range task.Required {
+		ex, err := r.Explain(name)
+		if err != nil {
+			return nil, err
+		}
+		if !ex.Holds {
+			violations = append(violations, ex)
+		}
+	}
+	for _, name := range task.Forbidden {
+		ex, err := r.Explain(name)
+		if err != nil {
+			return nil, err
+		}
+		if ex.Holds {
+			ex.Text += ". По условию это не должно выполняться"
+			violations = append(violations, ex)
+		}
+	}
+
+	if task.Classes > 0 && r.IsEquivalence() {
+		classes, _ := r.Quotient()
+		if len(classes) != task.Classes {
+			violations = append(violations, relation.Explanation{
+				Condition: trainerConditionClasses,
+				Text: fmt.Sprintf("Классов эквивалентности %d: %s, а нужно %d",
+					len(classes), classesText(classes), task.Classes),
+			})
+		}
+	}
+	if task.NotLattice && r.IsPartialOrder() && r.IsLattice() {
+		violations = append(violations, relation.Explanation{
+			Condition: trainerConditionLattice,
+			Text:      "Порядок является решеткой: у любых двух элементов есть точные верхняя и нижняя грани. Нужна пара элементов без супремума или инфимума",
+		})
+	}
+	if n := r.Len(); n < task.MinPairs {
+		violations = append(violations, relation.Explanation{
+			Condition: trainerConditionPairs,
+			Text:      fmt.Sprintf("Пар в отношении: %d, а нужно не меньше %d", n, task.MinPairs),
+		})
+	}
+	return violations, nil
+}
+
+func classesText(classes [][]string) string {
+	blocks := make([]string, len(classes))
+	for i, c := range classes {
+		blocks[i] = "{" + strings.Join(c, ", ") + "}"
+	}
+	return strings.Join(blocks, ", ")
+}
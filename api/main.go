@@ -5,6 +5,7 @@ import (
 	"api/internal/models"
 	"api/internal/repository"
 	"api/internal/service"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -567,7 +568,7 @@ func getTestsMap(db *sql.DB, courseIDs []int) (map[int][]models.Test, error) {
 	rows, err := db.Query(`
         SELECT id, name, id_course, upload_date, ends_date, duration, attempts 
         FROM tests 
-        WHERE id_course = ANY($1)
+        WHERE id_course = ANY($1) AND deleted_at IS NULL
     `, pq.Array(courseIDs))
 	if err != nil {
 		return nil, err
@@ -814,7 +815,7 @@ WHERE row_num = $2`, username, i).Scan(&id, &name)
 
 		// Получаем тесты курса
 		var testsCount int
-		err = Db.QueryRow(`SELECT COUNT(*) FROM tests WHERE id_course = $1`, id).Scan(&testsCount)
+		err = Db.QueryRow(`SELECT COUNT(*) FROM tests WHERE id_course = $1 AND deleted_at IS NULL`, id).Scan(&testsCount)
 		if err != nil && err != sql.ErrNoRows {
 			log.Println("Ошибка базы данных")
 			sendError(w, "Ошибка базы данных "+err.Error(), http.StatusInternalServerError)
@@ -830,7 +831,7 @@ WHERE row_num = $2`, username, i).Scan(&id, &name)
 			err = Db.QueryRow(`WITH numbered_rows AS (
 									SELECT id, name, ends_date, duration, ROW_NUMBER() OVER (ORDER BY id DESC) as row_num
 								FROM tests
-								WHERE id_course = $1
+								WHERE id_course = $1 AND deleted_at IS NULL
 							)
 							SELECT id, name, ends_date, duration
 							FROM numbered_rows
@@ -1123,7 +1124,7 @@ func getTestsData(w http.ResponseWriter, r *http.Request) {
 						JOIN groups_courses gc ON g.id = gc.id_group
 						JOIN courses c ON gc.id_course = c.id
 						JOIN tests t ON c.id = t.id_course
-						WHERE u.username = $1`, username).Scan(&testsCount)
+						WHERE u.username = $1 AND t.deleted_at IS NULL`, username).Scan(&testsCount)
 	if err != nil {
 		log.Println("Ошибка базы данных")
 		sendError(w, "Ошибка базы данных "+err.Error(), http.StatusInternalServerError)
@@ -1152,7 +1153,7 @@ func getTestsData(w http.ResponseWriter, r *http.Request) {
     							JOIN groups_courses gc ON g.id = gc.id_group
     							JOIN courses c ON gc.id_course = c.id
     							JOIN tests t ON c.id = t.id_course
-    							WHERE u.username = $1
+    							WHERE u.username = $1 AND t.deleted_at IS NULL
 							)
 							SELECT id, name, upload_date, ends_date, duration, attempts
 							FROM numbered_rows
@@ -1249,7 +1250,7 @@ func getCourseData(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var testsCount int
-	err = Db.QueryRow("SELECT COUNT(*) FROM tests WHERE id_course = $1", courseID).Scan(&testsCount)
+	err = Db.QueryRow("SELECT COUNT(*) FROM tests WHERE id_course = $1 AND deleted_at IS NULL", courseID).Scan(&testsCount)
 	if err == sql.ErrNoRows {
 		log.Println("Неправильные данные")
 		sendError(w, "Неправильные данные", http.StatusUnauthorized)
@@ -1264,7 +1265,7 @@ func getCourseData(w http.ResponseWriter, r *http.Request) {
 	for i := 1; i <= testsCount; i++ {
 		var title string
 		var EndDate time.Time
-		err = Db.QueryRow("SELECT name, ends_date FROM (SELECT *, ROW_NUMBER() OVER () as row_num FROM tests WHERE id_course = $1) AS subquery WHERE row_num = $2", courseID, i).Scan(&title, &EndDate)
+		err = Db.QueryRow("SELECT name, ends_date FROM (SELECT *, ROW_NUMBER() OVER () as row_num FROM tests WHERE id_course = $1 AND deleted_at IS NULL) AS subquery WHERE row_num = $2", courseID, i).Scan(&title, &EndDate)
 		if err == sql.ErrNoRows {
 			log.Println("Неправильные данные")
 			sendError(w, "Неправильные данные", http.StatusUnauthorized)
@@ -1534,21 +1535,15 @@ func handleDeleteCourse(w http.ResponseWriter, r *http.Request) {
 
 	// Проверка пройдена
 
-	_, err = Db.Exec("DELETE FROM users_courses WHERE id_course = $1", data.Id)
+	courseID, err := strconv.Atoi(data.Id)
 	if err != nil {
-		log.Println("Ошибка базы данных")
-		sendError(w, "Ошибка базы данных "+err.Error(), http.StatusInternalServerError)
+		log.Println("Некорректный ID курса")
+		sendError(w, "Некорректный ID курса", http.StatusBadRequest)
 		return
 	}
 
-	_, err = Db.Exec("DELETE FROM groups_courses WHERE id_course = $1", data.Id)
-	if err != nil {
-		log.Println("Ошибка базы данных")
-		sendError(w, "Ошибка базы данных "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	_, err = Db.Exec("DELETE FROM courses WHERE id = $1", data.Id)
+	// Курс, записи на него и тесты удаляются в одной транзакции; тесты с попытками скрываются
+	err = repository.NewTestRepository(Db).DeleteCourse(r.Context(), courseID)
 	if err != nil {
 		log.Println("Ошибка базы данных")
 		sendError(w, "Ошибка базы данных "+err.Error(), http.StatusInternalServerError)
@@ -1853,6 +1848,9 @@ func main() {
 	testService := service.NewTestService(testRepo)
 	testHandler := handler.NewTestHandler(testService)
 
+	// Фоновое завершение попыток, у которых истекло время
+	go testService.RunAttemptSweeper(context.Background(), time.Minute)
+
 	r.HandleFunc("/api/auth", handleAuth)
 	r.HandleFunc("/api/verify", verifyToken)
 	r.HandleFunc("/api/verifyadmin", verifyAdmin)
@@ -1879,10 +1877,47 @@ func main() {
 	// API tests-service
 	r.HandleFunc("/api/tests/", testHandler.CreateTest)
 	r.HandleFunc("/api/tests/test/{id}", testHandler.GetTest)
+	r.HandleFunc("/api/tests/release", testHandler.ReleaseResults)
+	r.HandleFunc("/api/tests/update", testHandler.UpdateTest)
+	r.HandleFunc("/api/tests/delete", testHandler.DeleteTest)
+	r.HandleFunc("/api/tests/export/{id:[0-9]+}", testHandler.ExportTest)
+	r.HandleFunc("/api/tests/import", testHandler.ImportTest)
+	r.HandleFunc("/api/tests/package/{id:[0-9]+}", testHandler.ExportPackage)
+	r.HandleFunc("/api/tests/package/import", testHandler.ImportPackage)
 	r.HandleFunc("/api/tests/attempts", testHandler.StartAttempt)
 
 	r.HandleFunc("/api/attempts/answers", testHandler.SubmitAnswer)
 	r.HandleFunc("/api/attempts/finish", testHandler.FinishAttempt)
+	r.HandleFunc("/api/attempts/current", testHandler.GetAttemptProgress)
+	r.HandleFunc("/api/attempts/review", testHandler.GetAttemptReview)
+
+	r.HandleFunc("/api/grading/queue", testHandler.GetGradingQueue)
+	r.HandleFunc("/api/grading/grade", testHandler.GradeAnswer)
+
+	r.HandleFunc("/api/banks", testHandler.GetCourseBanks)
+	r.HandleFunc("/api/banks/create", testHandler.CreateBank)
+	r.HandleFunc("/api/banks/update", testHandler.UpdateBank)
+	r.HandleFunc("/api/banks/delete", testHandler.DeleteBank)
+	r.HandleFunc("/api/banks/questions/create", testHandler.CreateBankQuestion)
+	r.HandleFunc("/api/banks/questions/update", testHandler.UpdateBankQuestion)
+	r.HandleFunc("/api/banks/questions/delete", testHandler.DeleteBankQuestion)
+	r.HandleFunc("/api/banks/{id:[0-9]+}", testHandler.GetBank)
+
+	r.HandleFunc("/api/relations/explain", testHandler.ExplainRelation)
+	r.HandleFunc("/api/relations/equivalence", testHandler.EquivalenceClasses)
+	r.HandleFunc("/api/relations/poset", testHandler.AnalyzePoset)
+	r.HandleFunc("/api/relations/parse", testHandler.ParseRelation)
+	r.HandleFunc("/api/relations/svg", testHandler.RenderRelation)
+	r.HandleFunc("/api/exercises/generate", testHandler.GenerateExercise)
+
+	r.HandleFunc("/api/trainer/relations", testHandler.GetTrainerRelations)
+	r.HandleFunc("/api/trainer/relations/get", testHandler.GetTrainerRelation)
+	r.HandleFunc("/api/trainer/relations/save", testHandler.SaveTrainerRelation)
+	r.HandleFunc("/api/trainer/relations/delete", testHandler.DeleteTrainerRelation)
+	r.HandleFunc("/api/trainer/check", testHandler.CheckTrainerRelation)
+	r.HandleFunc("/api/trainer/activity", testHandler.GetTrainerActivity)
+	r.HandleFunc("/api/trainer/tasks", testHandler.GetTrainerTasks)
+	r.HandleFunc("/api/trainer/tasks/verify", testHandler.VerifyTrainerTask)
 
 	// Запуск сервера (Ctrl + C, чтобы выключить)
 	err := http.ListenAndServe(port, r)
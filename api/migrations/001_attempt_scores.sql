@@ -0,0 +1,4 @@
+-- Итоговый балл попытки: набранные баллы, максимум и процент
+ALTER TABLE test_attempts
+    ADD COLUMN IF NOT EXISTS max_score INTEGER,
+    ADD COLUMN IF NOT EXISTS percentage NUMERIC(5, 2);
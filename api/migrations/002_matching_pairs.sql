@@ -0,0 +1,16 @@
+-- Режим начисления баллов за вопрос: all_or_nothing или partial
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS scoring_mode VARCHAR(32) NOT NULL DEFAULT 'all_or_nothing';
+
+-- Пары для вопросов на сопоставление.
+-- right_key — случайный идентификатор правого значения, по которому студент
+-- выбирает ответ (id пары не раскрывается, чтобы не выдавать соответствие)
+CREATE TABLE IF NOT EXISTS matching_pairs (
+    id          SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    left_text   TEXT    NOT NULL,
+    right_text  TEXT    NOT NULL,
+    right_key   INTEGER NOT NULL,
+    position    INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (question_id, right_key)
+);
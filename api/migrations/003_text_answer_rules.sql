@@ -0,0 +1,14 @@
+-- Правила проверки текстовых ответов.
+-- rule_type: exact, normalized, regex, numeric, pair_set
+CREATE TABLE IF NOT EXISTS text_answer_rules (
+    id          SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    rule_type   VARCHAR(32) NOT NULL,
+    pattern     TEXT NOT NULL,
+    tolerance   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    position    INTEGER NOT NULL DEFAULT 0
+);
+
+-- Ответы, которые не удалось проверить автоматически
+ALTER TABLE user_answers
+    ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,8 @@
+-- Ручная проверка ответов преподавателем
+ALTER TABLE user_answers
+    ADD COLUMN IF NOT EXISTS reviewer_comment TEXT,
+    ADD COLUMN IF NOT EXISTS reviewed_by INTEGER REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_user_answers_needs_review
+    ON user_answers (attempt_id) WHERE needs_review;
@@ -0,0 +1,6 @@
+-- Крайний срок попытки: время начала + длительность теста, но не позже ends_date
+ALTER TABLE test_attempts
+    ADD COLUMN IF NOT EXISTS deadline_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_test_attempts_in_progress_deadline
+    ON test_attempts (deadline_at) WHERE status = 'in_progress';
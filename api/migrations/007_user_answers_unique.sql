@@ -0,0 +1,13 @@
+-- Один ответ на вопрос в рамках попытки: повторная отправка обновляет ответ.
+-- Перед добавлением ограничения оставляем только последний из дублей.
+DELETE FROM user_answers a
+    USING user_answers b
+    WHERE a.attempt_id = b.attempt_id
+      AND a.question_id = b.question_id
+      AND a.id < b.id;
+
+ALTER TABLE user_answers
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+ALTER TABLE user_answers
+    ADD CONSTRAINT user_answers_attempt_question_key UNIQUE (attempt_id, question_id);
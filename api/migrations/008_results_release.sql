@@ -0,0 +1,3 @@
+-- Преподаватель открывает студентам просмотр теста после завершения
+ALTER TABLE tests
+    ADD COLUMN IF NOT EXISTS results_released BOOLEAN NOT NULL DEFAULT FALSE;
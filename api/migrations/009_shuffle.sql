@@ -0,0 +1,9 @@
+-- Настройки перемешивания вопросов и вариантов ответа
+ALTER TABLE tests
+    ADD COLUMN IF NOT EXISTS shuffle_questions BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS shuffle_options BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Зерно перестановки сохраняется в попытке, чтобы при продолжении
+-- и при просмотре результатов порядок был тем же
+ALTER TABLE test_attempts
+    ADD COLUMN IF NOT EXISTS shuffle_seed BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,20 @@
+-- Разделы теста: из пула вопросов раздела в каждую попытку попадает draw_count случайных
+CREATE TABLE IF NOT EXISTS test_sections (
+    id         SERIAL PRIMARY KEY,
+    test_id    INTEGER NOT NULL REFERENCES tests(id) ON DELETE CASCADE,
+    title      VARCHAR(255) NOT NULL DEFAULT '',
+    draw_count INTEGER NOT NULL CHECK (draw_count > 0),
+    position   INTEGER NOT NULL DEFAULT 0
+);
+
+-- Вопрос без раздела входит в каждую попытку
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS section_id INTEGER REFERENCES test_sections(id) ON DELETE SET NULL;
+
+-- Набор вопросов, выпавший конкретной попытке
+CREATE TABLE IF NOT EXISTS attempt_questions (
+    attempt_id  INTEGER NOT NULL REFERENCES test_attempts(id) ON DELETE CASCADE,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    PRIMARY KEY (attempt_id, question_id)
+);
@@ -0,0 +1,35 @@
+-- Банк вопросов курса. Вопрос банка хранится в questions с bank_id вместо test_id,
+-- тесты ссылаются на него через test_bank_questions, поэтому правка в банке
+-- сразу видна во всех тестах.
+CREATE TABLE IF NOT EXISTS question_banks (
+    id         SERIAL PRIMARY KEY,
+    course_id  INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    owner_id   INTEGER NOT NULL REFERENCES users(id),
+    title      VARCHAR(255) NOT NULL,
+    shared     BOOLEAN NOT NULL DEFAULT FALSE, -- доступен остальным преподавателям курса
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE questions
+    ALTER COLUMN test_id DROP NOT NULL;
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS bank_id    INTEGER REFERENCES question_banks(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS tags       TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS difficulty SMALLINT NOT NULL DEFAULT 0;
+
+-- Вопрос принадлежит либо тесту, либо банку
+ALTER TABLE questions
+    ADD CONSTRAINT questions_owner_check CHECK ((test_id IS NULL) <> (bank_id IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_questions_bank ON questions (bank_id) WHERE bank_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_questions_tags ON questions USING GIN (tags);
+
+-- Ссылки тестов на вопросы банка. Удаление используемого вопроса запрещено.
+CREATE TABLE IF NOT EXISTS test_bank_questions (
+    test_id     INTEGER NOT NULL REFERENCES tests(id) ON DELETE CASCADE,
+    question_id INTEGER NOT NULL REFERENCES questions(id),
+    position    INTEGER NOT NULL DEFAULT 0,
+    section_id  INTEGER REFERENCES test_sections(id) ON DELETE SET NULL,
+    PRIMARY KEY (test_id, question_id)
+);
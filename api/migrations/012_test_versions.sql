@@ -0,0 +1,23 @@
+-- Версии тестов. Изменение теста, по которому уже есть попытки, не трогает
+-- старые вопросы: они помечаются archived и остаются привязанными к прежним
+-- попыткам через attempt_questions, а новые попытки получают новую версию.
+ALTER TABLE tests
+    ADD COLUMN IF NOT EXISTS version    INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP; -- тест с попытками удаляется мягко
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE test_sections
+    ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE test_attempts
+    ADD COLUMN IF NOT EXISTS test_version INTEGER NOT NULL DEFAULT 1;
+
+-- Попытки, начатые до появления пулов, не имеют закрепленного набора вопросов.
+-- Закрепляем текущие вопросы теста, чтобы они пережили изменение теста.
+INSERT INTO attempt_questions (attempt_id, question_id, position)
+SELECT ta.id, q.id, ROW_NUMBER() OVER (PARTITION BY ta.id ORDER BY q.position, q.id)
+FROM test_attempts ta
+JOIN questions q ON q.test_id = ta.test_id
+WHERE NOT EXISTS (SELECT 1 FROM attempt_questions aq WHERE aq.attempt_id = ta.id);
@@ -0,0 +1,13 @@
+-- Постоянный внешний идентификатор теста. Он переносится в пакете теста,
+-- поэтому повторный импорт пакета на другом экземпляре находит тот же тест.
+ALTER TABLE tests
+    ADD COLUMN IF NOT EXISTS external_id TEXT;
+
+UPDATE tests SET external_id = gen_random_uuid()::text WHERE external_id IS NULL;
+
+ALTER TABLE tests
+    ALTER COLUMN external_id SET DEFAULT gen_random_uuid()::text,
+    ALTER COLUMN external_id SET NOT NULL;
+
+-- Мягко удаленный тест не мешает импортировать пакет заново
+CREATE UNIQUE INDEX IF NOT EXISTS tests_external_id_key ON tests (external_id) WHERE deleted_at IS NULL;
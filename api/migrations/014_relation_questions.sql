@@ -0,0 +1,12 @@
+-- Задания вопросов об отношениях.
+-- mode: pairs — ответ сравнивается с ожидаемым отношением pairs;
+-- properties — ответ должен обладать свойствами (или видами) из required
+-- и не обладать свойствами из forbidden
+CREATE TABLE IF NOT EXISTS relation_specs (
+    question_id INTEGER PRIMARY KEY REFERENCES questions(id) ON DELETE CASCADE,
+    elements    TEXT[]      NOT NULL,
+    mode        VARCHAR(32) NOT NULL CHECK (mode IN ('pairs', 'properties')),
+    pairs       JSONB       NOT NULL DEFAULT '[]',
+    required    TEXT[]      NOT NULL DEFAULT '{}',
+    forbidden   TEXT[]      NOT NULL DEFAULT '{}'
+);
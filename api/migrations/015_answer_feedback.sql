@@ -0,0 +1,4 @@
+-- Разбор автоматически проверенного ответа (для вопросов об отношениях:
+-- пропущенные и лишние пары, нарушенные условия с контрпримерами)
+ALTER TABLE user_answers
+    ADD COLUMN IF NOT EXISTS feedback JSONB;
@@ -0,0 +1,9 @@
+-- Вопросы об отношении со сгенерированным упражнением: каждая попытка получает
+-- свое отношение, построенное по виду exercise на множестве из size элементов
+ALTER TABLE relation_specs
+    ADD COLUMN IF NOT EXISTS exercise VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS size     INTEGER     NOT NULL DEFAULT 0;
+
+ALTER TABLE relation_specs DROP CONSTRAINT IF EXISTS relation_specs_mode_check;
+ALTER TABLE relation_specs ADD CONSTRAINT relation_specs_mode_check
+    CHECK (mode IN ('pairs', 'properties', 'generated'));
@@ -0,0 +1,5 @@
+-- Вопросы на разбиение: студент делит множество на классы эквивалентности
+-- отношения pairs
+ALTER TABLE relation_specs DROP CONSTRAINT IF EXISTS relation_specs_mode_check;
+ALTER TABLE relation_specs ADD CONSTRAINT relation_specs_mode_check
+    CHECK (mode IN ('pairs', 'properties', 'partition', 'generated'));
@@ -0,0 +1,26 @@
+-- Отношения, сохраненные студентом в тренажере. Имя уникально у пользователя,
+-- повторное сохранение с тем же именем перезаписывает отношение.
+CREATE TABLE IF NOT EXISTS trainer_relations (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    elements   TEXT[]       NOT NULL,
+    pairs      JSONB        NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Журнал проверок свойств в тренажере. relation_key — хэш канонического вида
+-- отношения, по нему считается число разных исследованных отношений;
+-- condition пуст, если проверялись все свойства сразу.
+CREATE TABLE IF NOT EXISTS trainer_checks (
+    id           SERIAL PRIMARY KEY,
+    user_id      INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    relation_key VARCHAR(32) NOT NULL,
+    condition    VARCHAR(32) NOT NULL DEFAULT '',
+    holds        BOOLEAN,
+    checked_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trainer_checks_user ON trainer_checks (user_id, checked_at);
@@ -0,0 +1,10 @@
+-- Прогресс студентов по заданиям тренажера. Задания описаны в коде сервиса,
+-- здесь хранится только их идентификатор, число проверок и время решения.
+CREATE TABLE IF NOT EXISTS trainer_task_progress (
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id    VARCHAR(64) NOT NULL,
+    attempts   INTEGER     NOT NULL DEFAULT 0,
+    solved_at  TIMESTAMP,
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, task_id)
+);
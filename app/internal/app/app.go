@@ -42,6 +42,9 @@ func Run(ctx context.Context) error {
 	r.HandleFunc("/teachercourses", handlers.ServeTeacherCoursesPage)
 	r.HandleFunc("/notifications", handlers.ServeNotificationsPage)
 	r.HandleFunc("/trainer", handlers.ServeTrainerPage)
+	r.HandleFunc("/grading", handlers.ServeGradingPage)
+	r.HandleFunc("/practice", handlers.ServePracticePage)
+	r.HandleFunc("/banks/{id}", handlers.ServeBankPage)
 	r.HandleFunc("/course/{name}", handlers.ServeCoursePage)
 	r.HandleFunc("/view/{name}", handlers.ServeViewPage)
 	r.HandleFunc("/test/create/{id}", handlers.ServeCreateTestPage)
@@ -79,9 +82,46 @@ func Run(ctx context.Context) error {
 	r.HandleFunc("/api/tests", handlers.CreateTest)
 	r.HandleFunc("/api/tests/test/{id}", handlers.GetTest)
 	r.HandleFunc("/api/tests/startattempt", handlers.StartAttempt)
+	r.HandleFunc("/api/tests/release", handlers.ReleaseResults)
+	r.HandleFunc("/api/tests/update", handlers.UpdateTest)
+	r.HandleFunc("/api/tests/delete", handlers.DeleteTest)
+	r.HandleFunc("/api/tests/export/{id:[0-9]+}", handlers.ExportTest)
+	r.HandleFunc("/api/tests/import", handlers.ImportTest)
+	r.HandleFunc("/api/tests/package/{id:[0-9]+}", handlers.ExportPackage)
+	r.HandleFunc("/api/tests/package/import", handlers.ImportPackage)
 
 	r.HandleFunc("/api/attempts/answer", handlers.SubmitAnswer)
 	r.HandleFunc("/api/attempts/finish", handlers.FinishAttempt)
+	r.HandleFunc("/api/attempts/current", handlers.GetAttemptProgress)
+	r.HandleFunc("/api/attempts/review", handlers.GetAttemptReview)
+
+	r.HandleFunc("/api/grading/queue", handlers.GetGradingQueue)
+	r.HandleFunc("/api/grading/grade", handlers.GradeAnswer)
+
+	r.HandleFunc("/api/banks", handlers.GetCourseBanks)
+	r.HandleFunc("/api/banks/create", handlers.CreateBank)
+	r.HandleFunc("/api/banks/update", handlers.UpdateBank)
+	r.HandleFunc("/api/banks/delete", handlers.DeleteBank)
+	r.HandleFunc("/api/banks/questions/create", handlers.CreateBankQuestion)
+	r.HandleFunc("/api/banks/questions/update", handlers.UpdateBankQuestion)
+	r.HandleFunc("/api/banks/questions/delete", handlers.DeleteBankQuestion)
+	r.HandleFunc("/api/banks/{id:[0-9]+}", handlers.GetBank)
+
+	r.HandleFunc("/api/relations/explain", handlers.ExplainRelation)
+	r.HandleFunc("/api/relations/equivalence", handlers.EquivalenceClasses)
+	r.HandleFunc("/api/relations/poset", handlers.AnalyzePoset)
+	r.HandleFunc("/api/relations/parse", handlers.ParseRelation)
+	r.HandleFunc("/api/relations/svg", handlers.RenderRelation)
+	r.HandleFunc("/api/exercises/generate", handlers.GenerateExercise)
+
+	r.HandleFunc("/api/trainer/relations", handlers.GetTrainerRelations)
+	r.HandleFunc("/api/trainer/relations/get", handlers.GetTrainerRelation)
+	r.HandleFunc("/api/trainer/relations/save", handlers.SaveTrainerRelation)
+	r.HandleFunc("/api/trainer/relations/delete", handlers.DeleteTrainerRelation)
+	r.HandleFunc("/api/trainer/check", handlers.CheckTrainerRelation)
+	r.HandleFunc("/api/trainer/activity", handlers.GetTrainerActivity)
+	r.HandleFunc("/api/trainer/tasks", handlers.GetTrainerTasks)
+	r.HandleFunc("/api/trainer/tasks/verify", handlers.VerifyTrainerTask)
 
 	http.Handle("/", r)
 
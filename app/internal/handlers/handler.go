@@ -383,6 +383,36 @@ func ServeTrainerPage(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, nil)
 }
 
+// Страница практики групп в тренажере для преподавателя
+func ServePracticePage(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("templates/practice.html")
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, nil)
+}
+
+// Страница ручной проверки ответов
+func ServeGradingPage(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("templates/grading.html")
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, nil)
+}
+
+// Страница банка вопросов курса
+func ServeBankPage(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("templates/banks.html")
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+	tmpl.Execute(w, nil)
+}
+
 // Страница админ панели
 func ServeAdminPage(w http.ResponseWriter, r *http.Request) {
 	// TODO: Проверять, авторизован ли пользователь в учетку админа
@@ -2150,8 +2180,15 @@ func GetTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Отправка запроса на другой сервер
-	resp, err := http.Get("http://localhost:1337/api/tests/test/" + id)
+	// Отправка запроса на другой сервер (токен пересылается, от роли зависит ответ)
+	req, err := http.NewRequest("GET", "http://localhost:1337/api/tests/test/"+id, nil)
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	req.Header.Set("Authorization", r.Header.Get("Authorization"))
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		slog.Info("Ошибка при получении теста")
 		http.Error(w, "Ошибка сервера авторизации", http.StatusInternalServerError)
@@ -2298,6 +2335,104 @@ func FinishAttempt(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+// Изменение теста; если по нему есть попытки, сервер создает новую версию
+func UpdateTest(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/tests/update", "Ошибка изменения теста")
+}
+
+func DeleteTest(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/tests/delete", "Ошибка удаления теста")
+}
+
+// Экспорт теста в Moodle XML или GIFT (?format=moodle|gift)
+func ExportTest(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	forwardGet(w, r, "http://localhost:1337/api/tests/export/"+id+"?"+r.URL.RawQuery, "Ошибка экспорта теста")
+}
+
+// Импорт теста из Moodle XML или GIFT; с dry_run сервер только проверяет файл
+func ImportTest(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/tests/import", "Ошибка импорта теста")
+}
+
+// Пакет теста в собственном формате для переноса между серверами
+func ExportPackage(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	forwardGet(w, r, "http://localhost:1337/api/tests/package/"+id, "Ошибка выгрузки пакета теста")
+}
+
+func ImportPackage(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/tests/package/import", "Ошибка импорта пакета теста")
+}
+
+// Открыть/закрыть студентам просмотр теста после попыток
+func ReleaseResults(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/tests/release", "Ошибка изменения доступа к результатам")
+}
+
+// Текущая попытка и сохраненные ответы (для продолжения после перезагрузки)
+func GetAttemptProgress(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/attempts/current", "Ошибка получения попытки")
+}
+
+// Разбор завершенной попытки с баллами и разбором ошибок
+func GetAttemptReview(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/attempts/review", "Ошибка получения разбора попытки")
+}
+
+// Ручная проверка
+func GetGradingQueue(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/grading/queue", "Ошибка получения ответов на проверку")
+}
+
+func GradeAnswer(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/grading/grade", "Ошибка сохранения оценки")
+}
+
+// Пересылает тело POST-запроса на сервер API и возвращает его ответ без изменений
+func forwardPost(w http.ResponseWriter, r *http.Request, url string, errMessage string) {
+	if r.Method != "POST" {
+		slog.Info("Метод не разрешен")
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Ошибка чтения запроса", http.StatusBadRequest)
+		return
+	}
+
+	// Отправка запроса на другой сервер
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		slog.Info(errMessage)
+		http.Error(w, errMessage, http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Чтение ответа от другого сервера
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, "Ошибка чтения ответа", http.StatusInternalServerError)
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Перенаправление ошибки от другого сервера
+		slog.Info(errMessage + ": " + strconv.Itoa(resp.StatusCode))
+		w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+		w.WriteHeader(resp.StatusCode)
+		w.Write(body)
+		return
+	}
+
+	// Успешный ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
 // Извлекает JWT-токен из строки HTTP-запроса
 func ExtractJWT(request string) string {
 	// Разбиваем запрос на строки
@@ -2329,3 +2464,164 @@ func UpdateStats() {
 		panic(err)
 	}
 }
+
+// Банки вопросов курса (?course_id=)
+func GetCourseBanks(w http.ResponseWriter, r *http.Request) {
+	forwardGet(w, r, "http://localhost:1337/api/banks?"+r.URL.RawQuery, "Ошибка получения банков вопросов")
+}
+
+// Банк вопросов с фильтрами ?tag= и ?difficulty=
+func GetBank(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	forwardGet(w, r, "http://localhost:1337/api/banks/"+id+"?"+r.URL.RawQuery, "Ошибка получения банка вопросов")
+}
+
+func CreateBank(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/banks/create", "Ошибка создания банка вопросов")
+}
+
+func UpdateBank(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/banks/update", "Ошибка изменения банка вопросов")
+}
+
+func DeleteBank(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/banks/delete", "Ошибка удаления банка вопросов")
+}
+
+func CreateBankQuestion(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/banks/questions/create", "Ошибка добавления вопроса в банк")
+}
+
+func UpdateBankQuestion(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/banks/questions/update", "Ошибка изменения вопроса банка")
+}
+
+func DeleteBankQuestion(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/banks/questions/delete", "Ошибка удаления вопроса банка")
+}
+
+// Проверка свойств отношения с объяснением для тренажера
+func ExplainRelation(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/relations/explain", "Ошибка проверки отношения")
+}
+
+// Классы эквивалентности и построение отношения по разбиению для тренажера
+func EquivalenceClasses(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/relations/equivalence", "Ошибка вычисления классов эквивалентности")
+}
+
+// Анализ частичного порядка (диаграмма Хассе, грани, решетка) для тренажера
+func AnalyzePoset(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/relations/poset", "Ошибка анализа частичного порядка")
+}
+
+// Разбор текстовой записи отношения для импорта в тренажер
+func ParseRelation(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/relations/parse", "Ошибка разбора записи отношения")
+}
+
+// Изображение отношения в SVG для вопросов, разбора попыток и выгрузки
+func RenderRelation(w http.ResponseWriter, r *http.Request) {
+	forwardGet(w, r, "http://localhost:1337/api/relations/svg?"+r.URL.RawQuery, "Ошибка построения изображения отношения")
+}
+
+// Предпросмотр сгенерированного упражнения по отношениям для преподавателя
+func GenerateExercise(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/exercises/generate", "Ошибка генерации упражнения")
+}
+
+// forwardGet пересылает GET-запрос на сервер API вместе с заголовком Authorization
+func forwardGet(w http.ResponseWriter, r *http.Request, url string, errMessage string) {
+	if r.Method != "GET" {
+		slog.Info("Метод не разрешен")
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	req.Header.Set("Authorization", r.Header.Get("Authorization"))
+	if tag := r.Header.Get("If-None-Match"); tag != "" {
+		req.Header.Set("If-None-Match", tag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Info(errMessage)
+		http.Error(w, errMessage, http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, "Ошибка чтения ответа", http.StatusInternalServerError)
+		return
+	}
+
+	// Заголовки кэширования API (изображения отношений) передаются браузеру как есть
+	for _, header := range []string{"ETag", "Cache-Control"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Перенаправление ошибки от другого сервера
+		slog.Info(errMessage + ": " + strconv.Itoa(resp.StatusCode))
+		w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+		w.WriteHeader(resp.StatusCode)
+		w.Write(body)
+		return
+	}
+
+	// Тип содержимого берется из ответа API: кроме JSON через прокси отдаются файлы экспорта
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != "" {
+		w.Header().Set("Content-Disposition", disposition)
+	}
+	w.Write(body)
+}
+
+// Сохраненные отношения тренажера: список, загрузка, сохранение и удаление
+func GetTrainerRelations(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/relations", "Ошибка получения отношений тренажера")
+}
+
+func GetTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/relations/get", "Ошибка загрузки отношения тренажера")
+}
+
+func SaveTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/relations/save", "Ошибка сохранения отношения тренажера")
+}
+
+func DeleteTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/relations/delete", "Ошибка удаления отношения тренажера")
+}
+
+// Проверка свойств в тренажере с записью в журнал практики
+func CheckTrainerRelation(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/check", "Ошибка проверки отношения")
+}
+
+// Практика группы в тренажере для преподавателя
+func GetTrainerActivity(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/activity", "Ошибка получения практики группы")
+}
+
+// Задания тренажера по уровням сложности и проверка решения
+func GetTrainerTasks(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/tasks", "Ошибка получения заданий тренажера")
+}
+
+func VerifyTrainerTask(w http.ResponseWriter, r *http.Request) {
+	forwardPost(w, r, "http://localhost:1337/api/trainer/tasks/verify", "Ошибка проверки решения задания")
+}
@@ -142,15 +142,34 @@ type CreateTestRequest struct {
 	Attempts  int                     `json:"attempts" validate:"min=0"`
 	EndDate   time.Time               `json:"end_date"`
 	Questions []CreateQuestionRequest `json:"questions"`
+	// Перемешивать вопросы и варианты ответа в каждой попытке
+	ShuffleQuestions bool `json:"shuffle_questions"`
+	ShuffleOptions   bool `json:"shuffle_options"`
+	// Разделы с пулами вопросов; вопрос ссылается на раздел по номеру
+	Sections []CreateSectionRequest `json:"sections,omitempty" validate:"dive"`
+}
+
+// ДTO для создания раздела теста
+type CreateSectionRequest struct {
+	Title     string `json:"title"`
+	DrawCount int    `json:"draw_count" validate:"min=1"`
+	Position  int    `json:"position" validate:"min=0"`
 }
 
 // ДTO для создания вопроса
 type CreateQuestionRequest struct {
-	QuestionText string                      `json:"question_text" validate:"required,min=3"`
-	QuestionType string                      `json:"question_type" validate:"required,oneof=single_choice multiple_choice text matching"`
-	Points       int                         `json:"points" validate:"min=0"`
-	Position     int                         `json:"position" validate:"min=0"`
-	Options      []CreateAnswerOptionRequest `json:"options,omitempty" validate:"dive"`
+	QuestionText string `json:"question_text" validate:"required,min=3"`
+	QuestionType string `json:"question_type" validate:"required,oneof=single_choice multiple_choice text matching relation"`
+	Points       int    `json:"points" validate:"min=0"`
+	Position     int    `json:"position" validate:"min=0"`
+	ScoringMode  string `json:"scoring_mode,omitempty" validate:"omitempty,oneof=all_or_nothing partial"`
+	Section      int    `json:"section,omitempty" validate:"min=0"` // номер раздела в sections с 1; 0 — без раздела
+	// Ссылка на вопрос банка курса вместо копии; остальные поля, кроме position и section, игнорируются
+	BankQuestionID int                           `json:"bank_question_id,omitempty"`
+	Options        []CreateAnswerOptionRequest   `json:"options,omitempty" validate:"dive"`
+	Pairs          []CreateMatchingPairRequest   `json:"pairs,omitempty" validate:"dive"`
+	AnswerRules    []CreateTextAnswerRuleRequest `json:"answer_rules,omitempty" validate:"dive"`
+	Relation       *RelationSpec                 `json:"relation,omitempty"`
 }
 
 // ДTO для создания варианта ответа
@@ -160,6 +179,41 @@ type CreateAnswerOptionRequest struct {
 	Position   int    `json:"position" validate:"min=0"`
 }
 
+// ДTO для создания пары сопоставления
+type CreateMatchingPairRequest struct {
+	LeftText  string `json:"left_text" validate:"required,min=1"`
+	RightText string `json:"right_text" validate:"required,min=1"`
+	Position  int    `json:"position" validate:"min=0"`
+}
+
+// ДTO для создания правила проверки текстового ответа
+type CreateTextAnswerRuleRequest struct {
+	RuleType  string  `json:"rule_type" validate:"required,oneof=exact normalized regex numeric pair_set"`
+	Pattern   string  `json:"pattern" validate:"required,min=1"`
+	Tolerance float64 `json:"tolerance" validate:"min=0"`
+	Position  int     `json:"position" validate:"min=0"`
+}
+
+// Задание вопроса об отношении: ожидаемые пары (mode=pairs) или свойства,
+// которые должны выполняться (required) и не выполняться (forbidden) (mode=properties),
+// разбиение множества на классы эквивалентности pairs (mode=partition)
+// или вид упражнения exercise на множестве из size элементов, свое для каждой попытки (mode=generated)
+type RelationSpec struct {
+	Elements  []string       `json:"elements" validate:"required,min=1"`
+	Mode      string         `json:"mode" validate:"required,oneof=pairs properties partition generated"`
+	Pairs     []RelationPair `json:"pairs,omitempty" validate:"dive"`
+	Required  []string       `json:"required,omitempty"`
+	Forbidden []string       `json:"forbidden,omitempty"`
+	Exercise  string         `json:"exercise,omitempty"`
+	Size      int            `json:"size,omitempty"`
+}
+
+// Упорядоченная пара отношения: From R To
+type RelationPair struct {
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+}
+
 type UserAnswer struct {
 	ID           int             `json:"id"`
 	AttemptID    int             `json:"attempt_id"`
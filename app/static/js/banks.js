@@ -0,0 +1,371 @@
+// ID курса берется из адреса страницы /banks/{id}
+const courseId = parseInt(window.location.pathname.split('/').filter(Boolean).pop());
+
+// Подсказки к полю с вариантами ответа для каждого типа вопроса
+const contentHints = {
+    single_choice: 'По одному варианту в строке, правильный отмечается звездочкой: * верный вариант',
+    multiple_choice: 'По одному варианту в строке, правильные отмечаются звездочкой: * верный вариант',
+    text: 'По одному правилу в строке: тип: образец (exact, normalized, regex, numeric, pair_set). Без типа — normalized',
+    matching: 'По одной паре в строке: левое = правое',
+    relation: 'Первая строка — элементы: a, b, c. Вторая — ожидаемые пары: (a, b), (b, c) или свойства: symmetric, !transitive (! — свойство не должно выполняться). Для классов эквивалентности — классы: {a, b}, {c}. Для своего упражнения в каждой попытке — строки упражнение: closure и размер: 5'
+};
+
+let currentBank = null;
+
+document.addEventListener('DOMContentLoaded', function() {
+    const token = localStorage.getItem('access_token'); // Получаем токен из localStorage
+
+    if (!token) {
+        // Токена нет
+        console.log("No token");
+        window.location.href = '/';
+        return;
+    }
+
+    document.getElementById('bankSelect').addEventListener('change', () => loadBank());
+    document.getElementById('applyFilter').addEventListener('click', () => loadBank());
+    document.getElementById('createBank').addEventListener('click', createBank);
+    document.getElementById('deleteBank').addEventListener('click', deleteBank);
+    document.getElementById('saveQuestion').addEventListener('click', saveQuestion);
+    document.getElementById('resetQuestion').addEventListener('click', resetQuestionForm);
+    document.getElementById('questionType').addEventListener('change', updateContentHint);
+
+    updateContentHint();
+    loadBanks();
+});
+
+function authHeaders() {
+    return { 'Authorization': localStorage.getItem('access_token') };
+}
+
+// POST-запрос к API банка; ошибка сервера превращается в исключение с его текстом
+function postJSON(url, data) {
+    data.token = localStorage.getItem('access_token');
+    return fetch(url, {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(data)
+    })
+    .then(response => {
+        if (!response.ok) {
+            return response.text().then(text => { throw new Error(text); });
+        }
+        return response.json();
+    });
+}
+
+function loadBanks(selectId) {
+    fetch(`http://localhost:9293/api/banks?course_id=${courseId}`, { headers: authHeaders() })
+    .then(response => {
+        if (!response.ok) {
+            throw new Error('Token invalid or expired');
+        }
+        return response.json();
+    })
+    .then(banks => {
+        const select = document.getElementById('bankSelect');
+        select.innerHTML = '';
+        banks.forEach(bank => {
+            const option = document.createElement('option');
+            option.value = bank.id;
+            option.textContent = bank.shared ? `${bank.title} (общий)` : bank.title;
+            select.appendChild(option);
+        });
+        if (selectId) {
+            select.value = selectId;
+        }
+        loadBank();
+    })
+    .catch(error => {
+        console.error('Ошибка:', error);
+        window.location.href = '/';
+    });
+}
+
+function loadBank() {
+    const bankId = document.getElementById('bankSelect').value;
+    const container = document.getElementById('questionsContainer');
+    container.innerHTML = '';
+    currentBank = null;
+
+    if (!bankId) {
+        document.getElementById('emptyBank').classList.remove('hidden');
+        return;
+    }
+
+    const params = new URLSearchParams({
+        tag: document.getElementById('filterTag').value.trim(),
+        difficulty: document.getElementById('filterDifficulty').value
+    });
+
+    fetch(`http://localhost:9293/api/banks/${bankId}?${params}`, { headers: authHeaders() })
+    .then(response => {
+        if (!response.ok) {
+            return response.text().then(text => { throw new Error(text); });
+        }
+        return response.json();
+    })
+    .then(bank => {
+        currentBank = bank;
+        renderQuestions(bank.questions || []);
+    })
+    .catch(error => {
+        console.error('Ошибка:', error);
+        alert('Не удалось загрузить банк: ' + error.message);
+    });
+}
+
+function renderQuestions(questions) {
+    const container = document.getElementById('questionsContainer');
+    document.getElementById('emptyBank').classList.toggle('hidden', questions.length > 0);
+
+    questions.forEach(question => {
+        const card = document.createElement('div');
+        card.className = 'question-card';
+        card.innerHTML = `
+            <h3></h3>
+            <div class="question-meta"></div>
+            <div class="question-key"></div>
+            <button type="button" class="btn btn-outline-primary btn-sm edit-question">Изменить</button>
+            <button type="button" class="btn btn-outline-danger btn-sm delete-question">Удалить</button>
+        `;
+
+        // Текст вставляется через textContent, чтобы он не интерпретировался как HTML
+        card.querySelector('h3').textContent = question.question_text;
+        const meta = [`${question.points} б.`];
+        if (question.difficulty) meta.push(`сложность ${question.difficulty}`);
+        if (question.tags.length) meta.push(question.tags.join(', '));
+        meta.push(`используется в тестах: ${question.used_in}`);
+        if (question.attempt_uses) meta.push(`в попытках: ${question.attempt_uses}`);
+        card.querySelector('.question-meta').textContent = meta.join(' · ');
+        card.querySelector('.question-key').textContent = contentToText(question);
+
+        card.querySelector('.edit-question').addEventListener('click', () => editQuestion(question));
+        card.querySelector('.delete-question').addEventListener('click', () => deleteQuestion(question));
+
+        container.appendChild(card);
+    });
+}
+
+// Представление ключа ответов в том же формате, в котором его вводит преподаватель
+function contentToText(question) {
+    switch (question.question_type) {
+        case 'single_choice':
+        case 'multiple_choice':
+            return (question.options || []).map(o => (o.is_correct ? '* ' : '') + o.option_text).join('\n');
+        case 'text_answer':
+            return (question.answer_rules || []).map(r => `${r.rule_type}: ${r.pattern}`).join('\n');
+        case 'matching':
+            return (question.pairs || []).map(p => `${p.left_text} = ${p.right_text}`).join('\n');
+        case 'relation': {
+            const relation = question.relation;
+            if (!relation) return '';
+            if (relation.mode === 'generated') {
+                return 'упражнение: ' + relation.exercise + '\nразмер: ' + relation.size;
+            }
+            if (relation.mode === 'partition') {
+                return 'элементы: ' + relation.elements.join(', ') + '\nклассы: ' + partitionText(relation);
+            }
+            const second = relation.mode === 'properties'
+                ? 'свойства: ' + (relation.required || []).concat((relation.forbidden || []).map(c => '!' + c)).join(', ')
+                : 'пары: ' + (relation.pairs || []).map(p => `(${p.from}, ${p.to})`).join(', ');
+            return 'элементы: ' + relation.elements.join(', ') + '\n' + second;
+        }
+    }
+    return '';
+}
+
+// Разбор поля с вариантами ответа в поля запроса создания вопроса
+function parseContent(type, text) {
+    const lines = text.split('\n').map(l => l.trim()).filter(l => l !== '');
+    const result = {};
+
+    if (type === 'single_choice' || type === 'multiple_choice') {
+        result.options = lines.map((line, index) => ({
+            option_text: line.replace(/^\*\s*/, ''),
+            is_correct: line.startsWith('*'),
+            position: index + 1
+        }));
+    } else if (type === 'text') {
+        result.answer_rules = lines.map((line, index) => {
+            const match = line.match(/^(exact|normalized|regex|numeric|pair_set):\s*(.*)$/);
+            return {
+                rule_type: match ? match[1] : 'normalized',
+                pattern: match ? match[2] : line,
+                position: index + 1
+            };
+        });
+    } else if (type === 'matching') {
+        result.pairs = lines.map((line, index) => {
+            const [left, ...right] = line.split('=');
+            return {
+                left_text: left.trim(),
+                right_text: right.join('=').trim(),
+                position: index + 1
+            };
+        });
+    } else if (type === 'relation') {
+        result.relation = parseRelationContent(lines);
+    }
+
+    return result;
+}
+
+// Разбор задания вопроса об отношении: строка с элементами и строка с парами или свойствами,
+// либо вид и размер упражнения, которое генерируется для каждой попытки
+function parseRelationContent(lines) {
+    const relation = { elements: [], mode: 'pairs', pairs: [], required: [], forbidden: [] };
+    const list = line => line.replace(/^[^:]*:/, '').split(',').map(s => s.trim()).filter(s => s !== '');
+
+    lines.forEach(line => {
+        if (/^элементы\s*:/i.test(line)) {
+            relation.elements = list(line);
+        } else if (/^классы\s*:/i.test(line)) {
+            relation.mode = 'partition';
+            for (const match of line.matchAll(/\{([^{}]*)\}/g)) {
+                const block = match[1].split(',').map(s => s.trim()).filter(s => s !== '');
+                block.forEach(from => block.forEach(to => relation.pairs.push({ from, to })));
+            }
+        } else if (/^упражнение\s*:/i.test(line)) {
+            relation.mode = 'generated';
+            relation.exercise = list(line)[0] || '';
+        } else if (/^размер\s*:/i.test(line)) {
+            relation.size = parseInt(list(line)[0]) || 0;
+        } else if (/^свойства\s*:/i.test(line)) {
+            relation.mode = 'properties';
+            list(line).forEach(c => {
+                if (c.startsWith('!')) {
+                    relation.forbidden.push(c.slice(1).trim());
+                } else {
+                    relation.required.push(c);
+                }
+            });
+        } else {
+            for (const match of line.matchAll(/\(\s*([^,()]+?)\s*,\s*([^,()]+?)\s*\)/g)) {
+                relation.pairs.push({ from: match[1], to: match[2] });
+            }
+        }
+    });
+
+    return relation;
+}
+
+// Классы эквивалентности по парам отношения: класс элемента — все, с кем он связан
+function partitionText(relation) {
+    const seen = new Set();
+    const classes = [];
+    relation.elements.forEach(e => {
+        if (seen.has(e)) return;
+        const block = relation.elements.filter(x => (relation.pairs || []).some(p => p.from === e && p.to === x));
+        block.forEach(x => seen.add(x));
+        classes.push('{' + block.join(', ') + '}');
+    });
+    return classes.join(', ');
+}
+
+function createBank() {
+    const title = document.getElementById('newBankTitle').value.trim();
+    if (!title) {
+        alert('Введите название банка');
+        return;
+    }
+
+    postJSON('http://localhost:9293/api/banks/create', {
+        course_id: courseId,
+        title: title,
+        shared: document.getElementById('newBankShared').checked
+    })
+    .then(bank => {
+        document.getElementById('newBankTitle').value = '';
+        loadBanks(bank.id);
+    })
+    .catch(error => alert('Не удалось создать банк: ' + error.message));
+}
+
+function deleteBank() {
+    if (!currentBank || !confirm(`Удалить банк «${currentBank.title}»?`)) {
+        return;
+    }
+
+    postJSON('http://localhost:9293/api/banks/delete', { bank_id: currentBank.id })
+    .then(() => loadBanks())
+    .catch(error => alert('Не удалось удалить банк: ' + error.message));
+}
+
+function saveQuestion() {
+    if (!currentBank) {
+        alert('Сначала создайте банк');
+        return;
+    }
+
+    const type = document.getElementById('questionType').value;
+    const questionId = parseInt(document.getElementById('editQuestionId').value);
+    const question = Object.assign({
+        question_text: document.getElementById('questionText').value,
+        question_type: type,
+        points: parseInt(document.getElementById('questionPoints').value),
+        position: 0
+    }, parseContent(type, document.getElementById('questionContent').value));
+
+    const request = {
+        bank_id: currentBank.id,
+        question_id: questionId,
+        question: question,
+        tags: document.getElementById('questionTags').value.split(','),
+        difficulty: parseInt(document.getElementById('questionDifficulty').value)
+    };
+
+    const url = questionId > 0
+        ? 'http://localhost:9293/api/banks/questions/update'
+        : 'http://localhost:9293/api/banks/questions/create';
+
+    postJSON(url, request)
+    .then(() => {
+        resetQuestionForm();
+        loadBank();
+    })
+    .catch(error => alert('Не удалось сохранить вопрос: ' + error.message));
+}
+
+function editQuestion(question) {
+    document.getElementById('questionFormTitle').textContent = 'Изменение вопроса';
+    document.getElementById('editQuestionId').value = question.id;
+    document.getElementById('questionText').value = question.question_text;
+    document.getElementById('questionType').value = question.question_type === 'text_answer' ? 'text' : question.question_type;
+    document.getElementById('questionPoints').value = question.points;
+    document.getElementById('questionDifficulty').value = question.difficulty;
+    document.getElementById('questionTags').value = question.tags.join(', ');
+    document.getElementById('questionContent').value = contentToText(question);
+    updateContentHint();
+    document.getElementById('questionForm').scrollIntoView();
+}
+
+function deleteQuestion(question) {
+    if (!confirm('Удалить вопрос из банка?')) {
+        return;
+    }
+
+    postJSON('http://localhost:9293/api/banks/questions/delete', { question_id: question.id })
+    .then(() => loadBank())
+    .catch(error => alert('Не удалось удалить вопрос: ' + error.message));
+}
+
+function resetQuestionForm() {
+    document.getElementById('questionFormTitle').textContent = 'Новый вопрос';
+    document.getElementById('editQuestionId').value = 0;
+    document.getElementById('questionText').value = '';
+    document.getElementById('questionContent').value = '';
+    document.getElementById('questionTags').value = '';
+    document.getElementById('questionDifficulty').value = 0;
+    updateContentHint();
+}
+
+function updateContentHint() {
+    document.getElementById('contentHint').textContent = contentHints[document.getElementById('questionType').value];
+}
+
+function handleRedirect() {
+    window.location.href = 'http://localhost:9293/profile';
+}
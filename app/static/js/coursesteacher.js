@@ -160,6 +160,18 @@ document.addEventListener('DOMContentLoaded', function() {
                                                         <span class="badge bg-info">
                                                             ${(parseInt(test.duration) / 60)} мин
                                                         </span>
+                                                        <button class="btn btn-outline-secondary btn-sm export-test" data-test-id="${test.id}" data-format="moodle">
+                                                            XML
+                                                        </button>
+                                                        <button class="btn btn-outline-secondary btn-sm export-test" data-test-id="${test.id}" data-format="gift">
+                                                            GIFT
+                                                        </button>
+                                                        <button class="btn btn-outline-secondary btn-sm export-test" data-test-id="${test.id}" data-format="package">
+                                                            JSON
+                                                        </button>
+                                                        <button class="btn btn-outline-danger btn-sm delete-test" data-test-id="${test.id}">
+                                                            Удалить
+                                                        </button>
                                                     </div>
                                                 </div>
                                             </li>
@@ -177,6 +189,22 @@ document.addEventListener('DOMContentLoaded', function() {
                                        target="_blank">
                                         Выбрать из готовых
                                     </a>
+                                    <a href="/banks/${course.id}" 
+                                       class="btn btn-outline-secondary"
+                                       target="_blank">
+                                        Банк вопросов
+                                    </a>
+                                </div>
+                                <div class="modal-footer import-test-form">
+                                    <select class="form-select form-select-sm w-auto import-format">
+                                        <option value="moodle">Moodle XML</option>
+                                        <option value="gift">GIFT</option>
+                                        <option value="package">Пакет теста (JSON)</option>
+                                    </select>
+                                    <input type="file" class="form-control form-control-sm w-auto import-file" accept=".xml,.txt,.gift">
+                                    <button class="btn btn-outline-primary btn-sm import-test" data-course-id="${course.id}">
+                                        Импортировать
+                                    </button>
                                 </div>
                             </div>
                         </div>
@@ -518,11 +546,151 @@ document.addEventListener('DOMContentLoaded', function() {
     }
 
     document.querySelector('table tbody').addEventListener('click', function(e) {
+        if (e.target.classList.contains('delete-test')) {
+            deleteTest(e.target);
+            return;
+        }
+        if (e.target.classList.contains('export-test')) {
+            exportTest(e.target);
+            return;
+        }
+        if (e.target.classList.contains('import-test')) {
+            importTest(e.target);
+            return;
+        }
         if (e.target.classList.contains('btn-danger')) {
             deleteCourse(e.target.closest('tr'));
         }
     });
 
+    // Тест с попытками скрывается на сервере, оценки по нему сохраняются
+    async function deleteTest(button) {
+        if (!confirm('Удалить тест?')) {
+            return;
+        }
+
+        try {
+            const response = await fetch(`/api/tests/delete`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                },
+                body: JSON.stringify({
+                    test_id: parseInt(button.dataset.testId),
+                    token: localStorage.getItem('access_token')
+                })
+            });
+
+            if (response.ok) {
+                button.closest('li').remove();
+            }
+        } catch (error) {
+            console.error('Ошибка удаления:', error);
+        }
+    }
+
+    // Файл экспорта отдается с заголовком Authorization, поэтому скачивается через fetch
+    async function exportTest(button) {
+        try {
+            const url = button.dataset.format === 'package'
+                ? `/api/tests/package/${button.dataset.testId}`
+                : `/api/tests/export/${button.dataset.testId}?format=${button.dataset.format}`;
+            const response = await fetch(url, {
+                headers: { 'Authorization': localStorage.getItem('access_token') }
+            });
+            if (!response.ok) {
+                throw new Error(await response.text());
+            }
+
+            const disposition = response.headers.get('Content-Disposition') || '';
+            const match = disposition.match(/filename="([^"]+)"/);
+            const link = document.createElement('a');
+            link.href = URL.createObjectURL(await response.blob());
+            link.download = match ? match[1] : `test-${button.dataset.testId}`;
+            link.click();
+            URL.revokeObjectURL(link.href);
+        } catch (error) {
+            alert('Не удалось экспортировать тест: ' + error.message);
+        }
+    }
+
+    // Сначала файл проверяется без сохранения (dry_run), затем преподаватель
+    // подтверждает импорт, увидев пропущенные вопросы и упрощения
+    async function importTest(button) {
+        const form = button.closest('.import-test-form');
+        const file = form.querySelector('.import-file').files[0];
+        if (!file) {
+            alert('Выберите файл');
+            return;
+        }
+
+        const format = form.querySelector('.import-format').value;
+        if (format === 'package') {
+            importPackage(parseInt(button.dataset.courseId), file);
+            return;
+        }
+
+        const request = {
+            token: localStorage.getItem('access_token'),
+            course_id: parseInt(button.dataset.courseId),
+            format: format,
+            content: await file.text(),
+            title: file.name.replace(/\.(gift\.txt|xml|txt|gift)$/i, ''),
+            end_date: new Date(Date.now() + 14 * 24 * 60 * 60 * 1000).toISOString()
+        };
+
+        try {
+            const report = await postImport(Object.assign({ dry_run: true }, request));
+            const issues = report.issues.map(issue =>
+                `${issue.severity === 'skipped' ? 'Пропущен' : 'Упрощен'} вопрос ${issue.question}` +
+                `${issue.title ? ` «${issue.title}»` : ''}: ${issue.message}`);
+            const summary = `Будет импортировано вопросов: ${report.imported}, пропущено: ${report.skipped}.`;
+            if (!confirm([summary, ...issues].join('\n'))) {
+                return;
+            }
+
+            await postImport(request);
+            location.reload();
+        } catch (error) {
+            alert('Не удалось импортировать тест: ' + error.message);
+        }
+    }
+
+    // Пакет с тем же внешним идентификатором обновляет уже импортированный тест
+    async function importPackage(courseId, file) {
+        const messages = {
+            created: 'Тест создан',
+            updated: 'Тест обновлен',
+            unchanged: 'Тест уже совпадает с пакетом'
+        };
+
+        try {
+            const result = await postImport({
+                token: localStorage.getItem('access_token'),
+                course_id: courseId,
+                package: JSON.parse(await file.text())
+            }, '/api/tests/package/import');
+            alert(`${messages[result.status]}: ${result.test.title}`);
+            location.reload();
+        } catch (error) {
+            alert('Не удалось импортировать пакет: ' + error.message);
+        }
+    }
+
+    async function postImport(request, url = '/api/tests/import') {
+        const response = await fetch(url, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json'
+            },
+            body: JSON.stringify(request)
+        });
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        return response.json();
+    }
+
     async function deleteCourse(row) {
         const courseData = {
             id: row.dataset.courseId,
@@ -243,18 +243,20 @@ document.addEventListener('DOMContentLoaded', function() {
 
                     if (question.type === 'matching') {
                         // Обработка вопроса на сопоставление
-                        question.left_items = [];
-                        question.right_items = [];
+                        question.pairs = [];
                         
                         const leftInputs = questionBlock.querySelectorAll('.matching-item input[type="text"]');
                         const rightSelects = questionBlock.querySelectorAll('.matching-item select');
                         
                         leftInputs.forEach((input, i) => {
-                            question.left_items.push(input.value);
-                            question.right_items.push(rightSelects[i].value);
+                            // Текст правой части берётся из выбранного пункта: список rightOptions виден только внутри блока вопроса
+                            const select = rightSelects[i];
+                            question.pairs.push({
+                                left_text: input.value,
+                                right_text: select.value !== '' ? select.selectedOptions[0].textContent : '',
+                                position: i + 1
+                            });
                         });
-                        
-                        question.right_options = [...rightOptions];
                     } else if (question.type !== 'text') {
                         // Обработка вопросов с вариантами ответов
                         question.options = [];
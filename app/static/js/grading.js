@@ -0,0 +1,115 @@
+document.addEventListener('DOMContentLoaded', function() {
+    const token = localStorage.getItem('access_token'); // Получаем токен из localStorage
+
+    if (!token) {
+        // Токена нет
+        console.log("No token");
+        window.location.href = '/';
+        return;
+    }
+
+    loadQueue(token);
+});
+
+// Загрузка очереди ответов на проверку
+function loadQueue(token) {
+    fetch('http://localhost:9293/api/grading/queue', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ token: token })
+    })
+    .then(response => {
+        if (!response.ok) {
+            throw new Error('Token invalid or expired');
+        }
+        return response.json();
+    })
+    .then(answers => renderQueue(answers))
+    .catch(error => {
+        console.error('Ошибка:', error);
+        window.location.href = '/';
+    });
+}
+
+function renderQueue(answers) {
+    const container = document.getElementById('answersContainer');
+    container.innerHTML = '';
+
+    document.getElementById('emptyQueue').classList.toggle('hidden', answers.length > 0);
+
+    answers.forEach(answer => {
+        const card = document.createElement('div');
+        card.className = 'answer-card';
+        card.innerHTML = `
+            <h3></h3>
+            <div class="answer-meta"></div>
+            <div class="answer-text"></div>
+            <div class="grade-form">
+                <input type="number" class="form-control grade-points" min="0" max="${answer.max_points}" value="0">
+                <span class="mt-2">из ${answer.max_points}</span>
+                <textarea class="form-control grade-comment" rows="1" placeholder="Комментарий"></textarea>
+                <button type="button" class="btn btn-primary grade-submit">Оценить</button>
+            </div>
+        `;
+
+        // Текст вставляется через textContent, чтобы ответ студента не интерпретировался как HTML
+        card.querySelector('h3').textContent = answer.question_text;
+        card.querySelector('.answer-meta').textContent =
+            `${answer.username} · ${answer.course_name} · ${answer.test_title}`;
+        card.querySelector('.answer-text').textContent = answerText(answer.answer_data);
+
+        card.querySelector('.grade-submit').addEventListener('click', function() {
+            const points = parseInt(card.querySelector('.grade-points').value);
+            const comment = card.querySelector('.grade-comment').value;
+            gradeAnswer(answer.answer_id, points, comment, card);
+        });
+
+        container.appendChild(card);
+    });
+}
+
+// Извлечение текста ответа из answer_data
+function answerText(data) {
+    if (data && typeof data.answer_text === 'string') {
+        return data.answer_text;
+    }
+    return JSON.stringify(data);
+}
+
+function gradeAnswer(answerId, points, comment, card) {
+    const token = localStorage.getItem('access_token');
+
+    fetch('http://localhost:9293/api/grading/grade', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({
+            token: token,
+            answer_id: answerId,
+            points: points,
+            comment: comment
+        })
+    })
+    .then(response => {
+        if (!response.ok) {
+            return response.text().then(text => { throw new Error(text); });
+        }
+        return response.json();
+    })
+    .then(() => {
+        card.remove();
+        const container = document.getElementById('answersContainer');
+        document.getElementById('emptyQueue').classList.toggle('hidden', container.children.length > 0);
+    })
+    .catch(error => {
+        console.error('Ошибка:', error);
+        alert('Не удалось сохранить оценку: ' + error.message);
+    });
+}
+
+function handleRedirect() {
+    window.location.href = 'http://localhost:9293/profile';
+}
@@ -0,0 +1,128 @@
+document.addEventListener('DOMContentLoaded', function() {
+    const token = localStorage.getItem('access_token'); // Получаем токен из localStorage
+
+    if (!token) {
+        // Токена нет
+        console.log("No token");
+        window.location.href = '/';
+        return;
+    }
+
+    document.getElementById('showButton').addEventListener('click', () => loadActivity(token));
+    loadActivity(token);
+});
+
+// Названия свойств в подсказке к числу проверок
+const conditionTitles = {
+    all: 'все свойства',
+    reflexive: 'рефлексивность',
+    irreflexive: 'антирефлексивность',
+    symmetric: 'симметричность',
+    antisymmetric: 'антисимметричность',
+    transitive: 'транзитивность'
+};
+
+// Загрузка практики выбранной группы за период
+function loadActivity(token) {
+    const groupId = parseInt(document.getElementById('groupSelect').value) || 0;
+    const from = document.getElementById('fromDate').value;
+    const to = document.getElementById('toDate').value;
+
+    const request = { token: token, group_id: groupId };
+    if (from) {
+        request.from = new Date(from + 'T00:00:00').toISOString();
+    }
+    if (to) {
+        // Конец периода включает весь выбранный день
+        const end = new Date(to + 'T00:00:00');
+        end.setDate(end.getDate() + 1);
+        request.to = end.toISOString();
+    }
+
+    fetch('http://localhost:9293/api/trainer/activity', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(request)
+    })
+    .then(response => {
+        if (response.status === 401) {
+            throw new Error('Token invalid or expired');
+        }
+        if (!response.ok) {
+            return response.text().then(text => { throw new Error(text); });
+        }
+        return response.json();
+    })
+    .then(activity => {
+        renderGroups(activity.groups, activity.group_id);
+        renderStudents(activity.students);
+    })
+    .catch(error => {
+        console.error('Ошибка:', error);
+        if (error.message === 'Token invalid or expired') {
+            window.location.href = '/';
+            return;
+        }
+        alert('Не удалось загрузить практику группы: ' + error.message);
+    });
+}
+
+function renderGroups(groups, selected) {
+    const select = document.getElementById('groupSelect');
+    select.innerHTML = '';
+    groups.forEach(group => {
+        const option = document.createElement('option');
+        option.value = group.id;
+        option.textContent = group.name;
+        option.selected = group.id === selected;
+        select.appendChild(option);
+    });
+}
+
+function renderStudents(students) {
+    const table = document.getElementById('practiceTable');
+    table.innerHTML = '';
+
+    document.getElementById('emptyGroup').classList.toggle('hidden', students.length > 0);
+
+    students.forEach(student => {
+        const row = document.createElement('tr');
+        const cells = [
+            student.username,
+            durationText(student.time_spent_seconds),
+            student.relations_explored,
+            student.checks,
+            student.saved_relations,
+            student.solved_tasks,
+            student.last_activity ? new Date(student.last_activity).toLocaleString('ru-RU') : '—',
+            student.finished_attempts,
+            student.average_percentage != null ? student.average_percentage.toFixed(1) + '%' : '—'
+        ];
+        // Текст вставляется через textContent, чтобы имена не интерпретировались как HTML
+        cells.forEach((value, i) => {
+            const cell = document.createElement('td');
+            cell.textContent = value;
+            if (i === 3) {
+                cell.title = Object.entries(student.checks_by_condition)
+                    .map(([condition, count]) => (conditionTitles[condition] || condition) + ': ' + count)
+                    .join('\n');
+            }
+            row.appendChild(cell);
+        });
+        table.appendChild(row);
+    });
+}
+
+function durationText(seconds) {
+    const minutes = Math.round(seconds / 60);
+    if (minutes < 60) {
+        return minutes + ' мин';
+    }
+    return Math.floor(minutes / 60) + ' ч ' + (minutes % 60) + ' мин';
+}
+
+function handleRedirect() {
+    window.location.href = 'http://localhost:9293/profile';
+}
@@ -1011,8 +1011,11 @@ void getTestData(int selected_listview_item) {
 
     httplib::Client cli("localhost:9293");
 
-    // Выполнение GET-запроса
-    auto res = cli.Get("/api/tests/test/" + std::to_string(id));
+    // Выполнение GET-запроса (тест доступен только во время попытки)
+    httplib::Headers headers = {
+        {"Authorization", localStorage.GetAccessToken()}
+    };
+    auto res = cli.Get("/api/tests/test/" + std::to_string(id), headers);
 
     if (res && res->status == 200) {
         try {
@@ -1064,38 +1067,44 @@ int findChoiceId(const std::string& element_name) {
 }
 
 void startTest() {
-    getTestData(listViewElement);
-    
-    if (got_questions) {
-        // Начинаем попытку
-        httplib::Client cli("localhost:9293");
-
-        // POST-запрос с JSON
-        httplib::Headers headers = {
-            {"Content-Type", "application/json"}
-        };
-        std::string body = R"({"id": ")" + std::to_string(tests_data.Tests[listViewElement].Id) + R"(", "token": ")" + localStorage.GetAccessToken() + R"("})";
+    // Начинаем попытку: без нее сервер не отдает вопросы теста
+    httplib::Client cli("localhost:9293");
 
-        auto post_res = cli.Post("/api/tests/startattempt", headers, body, "application/json");
-        if (post_res && post_res->status == 200) {
-            try {
-                nlohmann::json j = nlohmann::json::parse(post_res->body);
+    // POST-запрос с JSON
+    httplib::Headers headers = {
+        {"Content-Type", "application/json"}
+    };
+    std::string body = R"({"id": ")" + std::to_string(tests_data.Tests[listViewElement].Id) + R"(", "token": ")" + localStorage.GetAccessToken() + R"("})";
 
-                attempt = j.get<TestAttempt>();
+    auto post_res = cli.Post("/api/tests/startattempt", headers, body, "application/json");
+    if (post_res && post_res->status == 200) {
+        try {
+            nlohmann::json j = nlohmann::json::parse(post_res->body);
 
-                test_started = true;
-                CurrentPage = 3;
-                disable_network_interface();
-            }
-            catch (const std::exception& e) {
-                std::cout << post_res->body << std::endl;
-                std::cerr << "JSON parse error: " << e.what() << std::endl;
-            }
+            attempt = j.get<TestAttempt>();
         }
-        else {
+        catch (const std::exception& e) {
+            std::cout << post_res->body << std::endl;
+            std::cerr << "JSON parse error: " << e.what() << std::endl;
             test_started = false;
+            return;
         }
     }
+    else {
+        test_started = false;
+        return;
+    }
+
+    getTestData(listViewElement);
+
+    if (got_questions) {
+        test_started = true;
+        CurrentPage = 3;
+        disable_network_interface();
+    }
+    else {
+        test_started = false;
+    }
 }
 
 void sendAnswers() {